@@ -0,0 +1,43 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//Backend opens the sessions the OGM runs its statements in. A neo4j.Driver is a Backend. Other backends, such as the
+//in memory graph of the inmemory package, run the OGM without a Neo4j database
+type Backend interface {
+	//NewSession opens a session on the database of config, with its access mode. The statements of the session wait
+	//for the transactions of its bookmarks
+	NewSession(config neo4j.SessionConfig) (neo4j.Session, error)
+}
+
+//getBackend returns the backend of config, or a driver connecting to the database of config when there is none
+func getBackend(config *Config) (Backend, error) {
+	if config.Backend != nil {
+		return config.Backend, nil
+	}
+	return getDriver(config.URI, config.Username, config.Password, config.LogLevel)
+}
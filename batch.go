@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//unwindStatement is an UNWIND statement creating the graphs of its rows
+type unwindStatement struct {
+	cypher string
+	rows   []map[string]interface{}
+	graphs map[string]graph
+}
+
+//saveBatch collects the new graphs of a save and creates them with UNWIND statements grouped by label and type.
+//Statements are run with at most size rows, new nodes before new relationships
+type saveBatch struct {
+	size                   int
+	nodeStatements         []*unwindStatement
+	relationshipStatements []*unwindStatement
+	statements             map[string]*unwindStatement
+	createdGraphs          []graph
+
+	//mergedGraphs are the new graphs merged on existing entities
+	mergedGraphs []graph
+}
+
+func newSaveBatch(size int) *saveBatch {
+	return &saveBatch{
+		size:       size,
+		statements: map[string]*unwindStatement{}}
+}
+
+func (b *saveBatch) add(g graph, cBuilder graphQueryBuilder, saveOptions *SaveOptions) {
+	cypher, row := cBuilder.getUnwindCreate(saveOptions)
+	statement := b.statements[cypher]
+	if statement == nil {
+		statement = &unwindStatement{cypher: cypher, graphs: map[string]graph{}}
+		b.statements[cypher] = statement
+		if _, isRelationship := g.(*relationship); isRelationship {
+			b.relationshipStatements = append(b.relationshipStatements, statement)
+		} else {
+			b.nodeStatements = append(b.nodeStatements, statement)
+		}
+	}
+	statement.rows = append(statement.rows, row)
+	statement.graphs[g.getSignature()] = g
+}
+
+//forEachRows calls f with the rows of the statements of the batch, at most size rows at a time. The rows of new nodes
+//come before the rows of new relationships
+func (b *saveBatch) forEachRows(f func(statement *unwindStatement, rows []map[string]interface{}) error) error {
+	for _, statements := range [2][]*unwindStatement{b.nodeStatements, b.relationshipStatements} {
+		for _, statement := range statements {
+			for begin := 0; begin < len(statement.rows); begin += b.size {
+				end := begin + b.size
+				if end > len(statement.rows) {
+					end = len(statement.rows)
+				}
+				if err := f(statement, statement.rows[begin:end]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//create runs the statements of the batch and updates the created graphs with database generated IDs
+func (b *saveBatch) create(ctx context.Context, cypherExecuter *cypherExecuter, counters *Counters) error {
+	return b.forEachRows(func(statement *unwindStatement, rows []map[string]interface{}) error {
+		return b.createRows(ctx, cypherExecuter, statement, rows, counters)
+	})
+}
+
+//getStatements returns the statements creating the graphs of the batch, without running them
+func (b *saveBatch) getStatements() []Statement {
+	var statements []Statement
+	b.forEachRows(func(statement *unwindStatement, rows []map[string]interface{}) error {
+		setRowsEndpoints(statement, rows)
+		statements = append(statements, Statement{statement.cypher, map[string]interface{}{"rows": rows}})
+		return nil
+	})
+	return statements
+}
+
+//getGraphs returns the graphs created by the batch, in the order they are created
+func (b *saveBatch) getGraphs() []graph {
+	var graphs []graph
+	b.forEachRows(func(statement *unwindStatement, rows []map[string]interface{}) error {
+		for _, row := range rows {
+			graphs = append(graphs, statement.graphs[row["ref"].(string)])
+		}
+		return nil
+	})
+	return graphs
+}
+
+//setRowsEndpoints sets the IDs of the start and end nodes of the relationships of rows
+func setRowsEndpoints(statement *unwindStatement, rows []map[string]interface{}) {
+	for _, row := range rows {
+		if r, isRelationship := statement.graphs[row["ref"].(string)].(*relationship); isRelationship {
+			row["start"] = r.nodes[startNode].getID()
+			row["end"] = r.nodes[endNode].getID()
+		}
+	}
+}
+
+func (b *saveBatch) createRows(ctx context.Context, cypherExecuter *cypherExecuter, statement *unwindStatement, rows []map[string]interface{}, counters *Counters) error {
+	var (
+		records []neo4j.Record
+		err     error
+	)
+	//Relationships are created between nodes with known IDs
+	setRowsEndpoints(statement, rows)
+	if records, err = counters.collect(cypherExecuter.execContext(ctx, statement.cypher, map[string]interface{}{"rows": rows})); err != nil {
+		return err
+	}
+	if len(records) != len(rows) {
+		//A merged entity failed the version check
+		for _, row := range rows {
+			if row["version"] != nil {
+				return ErrOptimisticLock
+			}
+		}
+		return errors.New("Entities to save weren't found in the database")
+	}
+	for _, record := range records {
+		g := statement.graphs[record.GetByIndex(0).(string)]
+		id := record.GetByIndex(1).(int64)
+		unloadGraphID(g, &id)
+		if len(record.Values()) > 2 && !record.GetByIndex(2).(bool) {
+			b.mergedGraphs = append(b.mergedGraphs, g)
+			continue
+		}
+		b.createdGraphs = append(b.createdGraphs, g)
+	}
+	return nil
+}
@@ -29,4 +29,10 @@ type Config struct {
 	Password       string
 	LogLevel       LogLevel
 	AllowCyclicRef bool
+
+	//Database is the database of the sessions created with NewSession. The default database of the server is used when it's empty
+	Database string
+
+	//Backend runs the statements of the OGM. A driver connecting to URI is used when it's nil
+	Backend Backend
 }
@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//Counters count the changes the statements of a save or a delete made to the database
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+}
+
+//add adds the counters of the summary of result, once its records are read
+func (c *Counters) add(result neo4j.Result) error {
+	if c == nil {
+		return nil
+	}
+	summary, err := result.Summary()
+	if err != nil {
+		return err
+	}
+	if counters := summary.Counters(); counters != nil {
+		c.NodesCreated += counters.NodesCreated()
+		c.NodesDeleted += counters.NodesDeleted()
+		c.RelationshipsCreated += counters.RelationshipsCreated()
+		c.RelationshipsDeleted += counters.RelationshipsDeleted()
+		c.PropertiesSet += counters.PropertiesSet()
+		c.LabelsAdded += counters.LabelsAdded()
+		c.LabelsRemoved += counters.LabelsRemoved()
+	}
+	return nil
+}
+
+//collect returns the records of result, like neo4j.Collect, and counts the changes of its statement
+func (c *Counters) collect(result neo4j.Result, err error) ([]neo4j.Record, error) {
+	var records []neo4j.Record
+	if records, err = neo4j.Collect(result, err); err != nil {
+		return nil, err
+	}
+	if err = c.add(result); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+//single returns the only record of result, like neo4j.Single, and counts the changes of its statement
+func (c *Counters) single(result neo4j.Result, err error) (neo4j.Record, error) {
+	var record neo4j.Record
+	if record, err = neo4j.Single(result, err); err != nil {
+		return nil, err
+	}
+	if err = c.add(result); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
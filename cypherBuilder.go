@@ -24,16 +24,22 @@ package gogm
 
 import (
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 type graphQueryBuilder interface {
-	getCreate() (string, string, map[string]interface{}, map[string]graph)
+	getCreate(saveOptions *SaveOptions) (string, string, map[string]interface{}, map[string]graph)
+	getMergeMatch(saveOptions *SaveOptions) (string, map[string]interface{})
 	getMatch() (string, map[string]interface{}, map[string]graph)
 	getSet() (string, map[string]interface{})
+	getUnwindCreate(saveOptions *SaveOptions) (string, map[string]interface{})
 	getDelete() (string, map[string]interface{}, map[string]graph)
-	getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{})
+	getSoftDelete(softDeleteValue interface{}) (string, map[string]interface{}, map[string]graph)
+	getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error)
 	getDeleteAll() (string, map[string]interface{})
+	getSoftDeleteAll(softDeleteValue interface{}) (string, map[string]interface{})
 	getCountEntitiesOfType() (string, map[string]interface{})
 
 	getGraph() graph
@@ -45,18 +51,20 @@ func newCypherBuilder(g graph, registry *registry, store store) (graphQueryBuild
 	var (
 		qGraphBuilder graphQueryBuilder
 		stored        graph
+		versionCheck  *versionCheck
 		err           error
 	)
 	if store != nil {
 		stored = store.get(g)
+		versionCheck = newVersionCheck(g, registry, stored)
 	}
 	switch v := g.(type) {
 	case *node:
-		if qGraphBuilder, err = newNodeCypherBuilder(v, registry, stored); err != nil {
+		if qGraphBuilder, err = newNodeCypherBuilder(v, registry, stored, versionCheck); err != nil {
 			return nil, err
 		}
 	case *relationship:
-		qGraphBuilder = newRelationshipCypherBuilder(v, registry, stored)
+		qGraphBuilder = newRelationshipCypherBuilder(v, registry, stored, versionCheck)
 	}
 	return qGraphBuilder, nil
 }
@@ -91,12 +99,18 @@ func getCreateSchemaStatement(metadata metadata) []string {
 		}
 	}
 
+	var uniqueNames []string
 	for name := range unique {
+		uniqueNames = append(uniqueNames, name)
+	}
+	sort.Strings(uniqueNames)
+	for _, name := range uniqueNames {
 		for _, label := range objectMetadata.thisStructLabel {
 			statements = append(statements, `CREATE CONSTRAINT ON (a:`+label+`) ASSERT a.`+name+` IS UNIQUE`)
 		}
 	}
 
+	sort.Strings(indexes)
 	compositeIndexes := strings.Join(indexes, indexDelim)
 	if compositeIndexes != emptyString {
 		for _, label := range objectMetadata.thisStructLabel {
@@ -106,3 +120,158 @@ func getCreateSchemaStatement(metadata metadata) []string {
 	}
 	return statements
 }
+
+//getMergeProperties returns the properties in propertyNames of g, for merging g.
+//It returns nil when g has no such properties or when any is null
+func getMergeProperties(g graph, propertyNames []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for _, propertyName := range propertyNames {
+		value := g.getProperties()[propertyName]
+		if value == nil {
+			return nil
+		}
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			value = v.Elem().Interface()
+		}
+		properties[propertyName] = value
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
+
+//getMergePattern returns the property map pattern of the properties in propertyNames of g, for merging g.
+//It returns an empty pattern when g has no such properties or when any is null
+func getMergePattern(g graph, propertyNames []string) (string, map[string]interface{}) {
+	var (
+		sign          = g.getSignature()
+		mergePatterns []string
+		properties    = getMergeProperties(g, propertyNames)
+		parameters    = map[string]interface{}{}
+	)
+	if properties == nil {
+		return emptyString, nil
+	}
+	for index, propertyName := range propertyNames {
+		parameterName := sign + "Merge" + strconv.Itoa(index)
+		mergePatterns = append(mergePatterns, "`"+propertyName+"`: $"+parameterName)
+		parameters[parameterName] = properties[propertyName]
+	}
+	return ` {` + strings.Join(mergePatterns, ", ") + `}`, parameters
+}
+
+//getUnwindMergePattern returns the property map pattern of the properties in propertyNames, for merging the
+//graphs of UNWIND rows on their merge properties
+func getUnwindMergePattern(propertyNames []string) string {
+	var mergePatterns []string
+	for _, propertyName := range propertyNames {
+		mergePatterns = append(mergePatterns, "`"+propertyName+"`: row.merge.`"+propertyName+"`")
+	}
+	if len(mergePatterns) == 0 {
+		return emptyString
+	}
+	return ` {` + strings.Join(mergePatterns, ", ") + `}`
+}
+
+//getMergeVersionCheck returns the clause checking that the entity matched by matchedRef, before the graph of sign is
+//merged on it, has the version of versionCheck. Merged graphs not matching an entity are created without check
+func getMergeVersionCheck(matchedRef string, sign string, versionCheck *versionCheck, parameters map[string]interface{}) string {
+	versionCQLRef := sign + "Version"
+	parameters[versionCQLRef] = versionCheck.version
+	return `WITH * WHERE ` + matchedRef + ` IS NULL OR coalesce(` + matchedRef + `.` + versionCheck.propertyName + `, 0) = $` + versionCQLRef + `
+	`
+}
+
+//getUnwindMergeVersionCheck returns the clause checking that the entities matched by the merge of UNWIND rows have
+//the versions of the rows
+func getUnwindMergeVersionCheck(variables string, versionCheck *versionCheck) string {
+	return `WITH ` + variables + ` WHERE matched IS NULL OR coalesce(matched.` + versionCheck.propertyName + `, 0) = row.version
+	`
+}
+
+//getLoadAllPathFilter returns the WHERE clause excluding the loaded paths through soft deleted entities
+func getLoadAllPathFilter(lo *LoadOptions, metadata metadata, registry *registry) (string, error) {
+	if lo.IncludeSoftDeleted {
+		return emptyString, nil
+	}
+	predicate, err := getSoftDeletedPathPredicate("path", metadata, registry)
+	if err != nil || predicate == emptyString {
+		return emptyString, err
+	}
+	return `WHERE ` + predicate + `
+	`, nil
+}
+
+//getLoadAllRootClauses returns the WHERE clause and the WITH clause sorting and paging the root entities referenced by ref on load.
+//It also returns the ORDER BY sub clause keeping the order of the root entities in the load result
+func getLoadAllRootClauses(ref string, IDs interface{}, customIDPropertyName string, lo *LoadOptions, metadata metadata, registry *registry) (string, string, string, map[string]interface{}, error) {
+	var (
+		parameters = map[string]interface{}{}
+		predicates []string
+		filter     string
+		page       = `WITH ` + ref
+		orderBy    string
+		err        error
+	)
+
+	if IDs != nil {
+		predicate := `ID(` + ref + `) IN $ids`
+		if customIDPropertyName != emptyString {
+			predicate = ref + `.` + customIDPropertyName + ` IN $ids`
+		}
+		predicates = append(predicates, predicate)
+		parameters["ids"] = IDs
+	}
+
+	if lo.Filter != nil {
+		var predicate string
+		if predicate, err = lo.Filter.getCypher(ref, metadata, registry, 0, parameters); err != nil {
+			return emptyString, emptyString, emptyString, nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if !lo.IncludeSoftDeleted {
+		if predicate := getNotSoftDeletedPredicate(ref, metadata); predicate != emptyString {
+			predicates = append(predicates, predicate)
+		}
+	}
+
+	if lo.Pagination != nil && lo.Pagination.After != nil {
+		var predicate string
+		if predicate, err = getKeysetCypher(ref, lo.SortOrders, lo.Pagination.After, metadata, parameters); err != nil {
+			return emptyString, emptyString, emptyString, nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if len(predicates) > 0 {
+		filter = `WHERE ` + strings.Join(predicates, booleanOperators[andOperator]) + `
+	`
+	}
+
+	if orderBy, err = getSortCypher(ref, lo.SortOrders, metadata); err != nil {
+		return emptyString, emptyString, emptyString, nil, err
+	}
+	if orderBy != emptyString {
+		page += ` ` + orderBy
+	}
+
+	if lo.Pagination != nil {
+		if lo.Pagination.Skip > 0 {
+			page += ` SKIP $skip`
+			parameters["skip"] = lo.Pagination.Skip
+		}
+		if lo.Pagination.Limit > 0 {
+			page += ` LIMIT $limit`
+			parameters["limit"] = lo.Pagination.Limit
+		}
+	}
+
+	return filter, page + `
+	`, orderBy, parameters, nil
+}
@@ -23,22 +23,49 @@
 package gogm
 
 import (
+	"context"
+	"time"
+
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
 type transactionExecuter func(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error)
 
 type cypherExecuter struct {
-	driver      neo4j.Driver
+	backend     Backend
 	accessMode  neo4j.AccessMode
+	database    string
 	transaction *transaction
+
+	//bookmarks are the bookmarks the driver sessions wait for before running statements. They are the bookmark
+	//of the last transaction of the session, or the bookmarks the session was created with
+	bookmarks []string
+}
+
+func newCypherExecuter(backend Backend, accessMode neo4j.AccessMode, database string, t *transaction, bookmarks ...string) *cypherExecuter {
+	return &cypherExecuter{backend, accessMode, database, nil, bookmarks}
 }
 
-func newCypherExecuter(driver neo4j.Driver, accessMode neo4j.AccessMode, t *transaction) *cypherExecuter {
-	return &cypherExecuter{driver, accessMode, nil}
+//newDriverSession opens a session of backend on database with accessMode. All the driver sessions of the OGM are opened here
+func newDriverSession(backend Backend, accessMode neo4j.AccessMode, database string, bookmarks ...string) (neo4j.Session, error) {
+	return backend.NewSession(neo4j.SessionConfig{AccessMode: accessMode, Bookmarks: bookmarks, DatabaseName: database})
+}
+
+//setLastBookmark keeps the bookmark of the last transaction of session, for the next driver sessions to read its changes
+func (c *cypherExecuter) setLastBookmark(session neo4j.Session) {
+	if bookmark := session.LastBookmark(); bookmark != emptyString {
+		c.bookmarks = []string{bookmark}
+	}
 }
 
-func (c *cypherExecuter) execTransaction(te transactionExecuter, cql string, params map[string]interface{}) (neo4j.Result, error) {
+func (c *cypherExecuter) lastBookmark() string {
+	if len(c.bookmarks) == 0 {
+		return emptyString
+	}
+	return c.bookmarks[len(c.bookmarks)-1]
+}
+
+func (c *cypherExecuter) execTransaction(te transactionExecuter, cql string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
 	var (
 		err    error
 		result neo4j.Result
@@ -49,7 +76,7 @@ func (c *cypherExecuter) execTransaction(te transactionExecuter, cql string, par
 			return nil, err
 		}
 		return result, nil
-	}); err != nil {
+	}, configurers...); err != nil {
 		return nil, err
 	}
 
@@ -57,11 +84,21 @@ func (c *cypherExecuter) execTransaction(te transactionExecuter, cql string, par
 }
 
 func (c *cypherExecuter) exec(cql string, params map[string]interface{}) (neo4j.Result, error) {
+	return c.execContext(context.Background(), cql, params)
+}
+
+//execContext runs cql unless ctx is done. Outside a transaction, the deadline of ctx is the timeout of the transaction running cql
+func (c *cypherExecuter) execContext(ctx context.Context, cql string, params map[string]interface{}) (neo4j.Result, error) {
 	var (
-		result  neo4j.Result
-		session neo4j.Session
-		err     error
+		result    neo4j.Result
+		session   neo4j.Session
+		configure func(*neo4j.TransactionConfig)
+		err       error
 	)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if c.transaction != nil {
 		if result, err = c.transaction.run(cql, params); err != nil {
 			return nil, err
@@ -69,7 +106,11 @@ func (c *cypherExecuter) exec(cql string, params map[string]interface{}) (neo4j.
 		return result, nil
 	}
 
-	if session, err = c.driver.Session(c.accessMode); err != nil {
+	if configure, err = withContextTimeout(ctx); err != nil {
+		return nil, err
+	}
+
+	if session, err = newDriverSession(c.backend, c.accessMode, c.database, c.bookmarks...); err != nil {
 		return nil, err
 	}
 	defer session.Close()
@@ -79,9 +120,29 @@ func (c *cypherExecuter) exec(cql string, params map[string]interface{}) (neo4j.
 		transactionMode = session.WriteTransaction
 	}
 
-	return c.execTransaction(transactionMode, cql, params)
+	if result, err = c.execTransaction(transactionMode, cql, params, configure); err != nil {
+		return nil, err
+	}
+	c.setLastBookmark(session)
+	return result, nil
 }
 
 func (c *cypherExecuter) setTransaction(transaction *transaction) {
 	c.transaction = transaction
 }
+
+//withContextTimeout returns the configurer setting the transaction timeout to the time left before the deadline of ctx.
+//It fails with context.DeadlineExceeded when no time is left
+func withContextTimeout(ctx context.Context) (func(*neo4j.TransactionConfig), error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return func(*neo4j.TransactionConfig) {}, nil
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	return func(config *neo4j.TransactionConfig) {
+		config.Timeout = timeout
+	}, nil
+}
@@ -23,7 +23,11 @@
 package gogm
 
 import (
+	"context"
+	"errors"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
@@ -40,7 +44,7 @@ func newDeleter(cypherExecuter *cypherExecuter, store store, eventer eventer, re
 	return &deleter{cypherExecuter, store, eventer, registry, graphFactory}
 }
 
-func (d *deleter) delete(object interface{}) error {
+func (d *deleter) delete(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
 
 	var (
 		value              = reflect.ValueOf(object)
@@ -49,9 +53,21 @@ func (d *deleter) delete(object interface{}) error {
 		parameters         = []map[string]interface{}{}
 		graphDeleteClauses = map[clause][]string{}
 		graphs             []graph
+		cascadedGraphs     []graph
+		metadata           metadata
 		record             neo4j.Record
+		deletedAt          = time.Now()
 	)
 
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if deleteOptions == nil {
+		deleteOptions = NewDeleteOptions()
+	}
+	result := deleteOptions.newResult()
+
 	if graphs, err = d.graphFactory.get(value, map[int]bool{labels: true, relatedGraph: true}); err != nil {
 		return err
 	}
@@ -70,11 +86,20 @@ func (d *deleter) delete(object interface{}) error {
 		return nil
 	}
 
+	if storedGraph.getValue().IsValid() {
+		if metadata, err = d.registry.get(storedGraph.getValue().Type()); err != nil {
+			return err
+		}
+	}
+
 	var cypherBuilder graphQueryBuilder
 	if cypherBuilder, err = newCypherBuilder(storedGraph, d.registry, nil); err != nil {
 		return err
 	}
 	delete, deleteParameters, depedencies := cypherBuilder.getDelete()
+	if metadata != nil && metadata.getSoftDeleteBackendName() != emptyString {
+		delete, deleteParameters, depedencies = cypherBuilder.getSoftDelete(getSoftDeleteValue(metadata, deletedAt))
+	}
 	for _, depedency := range depedencies {
 		var depedencyCypherBuilder graphQueryBuilder
 		if depedencyCypherBuilder, err = newCypherBuilder(depedency, d.registry, nil); err != nil {
@@ -90,30 +115,58 @@ func (d *deleter) delete(object interface{}) error {
 	graphDeleteClauses[deleteClause] = append(graphDeleteClauses[deleteClause], delete)
 
 	cypher := getCyhperFromClauses(graphDeleteClauses)
-	if cypher != emptyString {
 
-		typeOfGraphToDelete := reflect.TypeOf(storedGraph)
-		for _, eventListener := range d.eventer.eventListeners {
-			eventListener.OnPreDelete(event{storedGraph.getValue(), DELETE})
-			if typeOfPrivateNode == typeOfGraphToDelete {
-				for _, relationship := range storedGraph.getRelatedGraphs() {
-					if relationship.getValue().IsValid() {
-						eventListener.OnPreDelete(event{relationship.getValue(), DELETE})
-					}
+	//The entity can veto the delete before the nodes deleted with it are looked for
+	if cypher != emptyString && !deleteOptions.DryRun {
+		if err = notifyPreDelete(d.eventer, storedGraph); err != nil {
+			return err
+		}
+		if typeOfPrivateNode == reflect.TypeOf(storedGraph) && !d.isSoftDeleted(storedGraph) {
+			//Soft deleted nodes keep their relationships
+			for _, relationship := range storedGraph.getRelatedGraphs() {
+				if err = notifyPreDelete(d.eventer, relationship); err != nil {
+					return err
 				}
 			}
 		}
+	}
 
-		if record, err = neo4j.Single(d.cypherExecuter.exec(cypher, flattenParamters(parameters))); err != nil {
+	if typeOfPrivateNode == reflect.TypeOf(storedGraph) {
+		if cascadedGraphs, err = d.getCascadedGraphs(ctx, metadata, []int64{storedGraph.getID()}, deleteOptions); err != nil {
 			return err
 		}
-		if record != nil {
-			deletedGraphs, updatedGraphs := d.store.delete(storedGraph)
-			for _, updatedGraph := range updatedGraphs {
-				notifyPostDelete(d.eventer, updatedGraph, UPDATE)
+	}
+
+	result.Deleted = getObjects(append([]graph{storedGraph}, cascadedGraphs...))
+
+	var cascadeDelete string
+	var cascadeDeleteParameters map[string]interface{}
+	if cascadeDelete, cascadeDeleteParameters, err = d.getCascadeDelete(cascadedGraphs, deletedAt); err != nil {
+		return err
+	}
+	parameters = append(parameters, cascadeDeleteParameters)
+
+	if deleteOptions.DryRun {
+		result.Statements = []Statement{{cascadeDelete + cypher, flattenParamters(parameters)}}
+		return nil
+	}
+
+	if cypher != emptyString {
+		for _, cascadedGraph := range cascadedGraphs {
+			if err = notifyPreDelete(d.eventer, cascadedGraph); err != nil {
+				return err
 			}
-			for _, deletedGraph := range deletedGraphs {
-				notifyPostDelete(d.eventer, deletedGraph, DELETE)
+		}
+
+		if record, err = result.Counters.single(d.cypherExecuter.execContext(ctx, cascadeDelete+cypher, flattenParamters(parameters))); err != nil {
+			return err
+		}
+		if record != nil {
+			for _, deletedGraph := range append([]graph{storedGraph}, cascadedGraphs...) {
+				if err = d.unloadSoftDeleted(deletedGraph, deletedAt); err != nil {
+					return err
+				}
+				d.deleteFromStore(deletedGraph)
 			}
 		}
 	}
@@ -121,50 +174,494 @@ func (d *deleter) delete(object interface{}) error {
 	return nil
 }
 
-func (d *deleter) deleteAll(object interface{}, deleteOptions *DeleteOptions) error {
+func (d *deleter) deleteAll(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
 	var (
-		value   = reflect.ValueOf(object)
-		graphs  []graph
-		err     error
-		records []neo4j.Record
+		value          = reflect.ValueOf(object)
+		graphs         []graph
+		deletedGraphs  []graph
+		cascadedGraphs []graph
+		metadata       metadata
+		err            error
+		records        []neo4j.Record
+		deletedAt      = time.Now()
 	)
 
+	if deleteOptions == nil {
+		deleteOptions = NewDeleteOptions()
+	}
+	result := deleteOptions.newResult()
+
 	if graphs, err = d.graphFactory.get(value, map[int]bool{labels: true}); err != nil {
 		return err
 	}
 
+	if metadata, err = d.registry.get(graphs[0].getValue().Type()); err != nil {
+		return err
+	}
+
+	//Find the entities to delete when they're needed to cascade the deletion, veto it or tell what a dry run deletes
+	var isCascaded, isFound bool
+	if isCascaded, err = isCascadeDeleted(metadata, deleteOptions); err != nil {
+		return err
+	}
+	if isCascaded || metadata.getSoftDeleteBackendName() != emptyString || deleteOptions.DryRun || isDeleteVetoable(d.eventer, metadata) {
+		if deletedGraphs, err = d.getDeletedGraphs(ctx, graphs[0], metadata); err != nil {
+			return err
+		}
+		isFound = true
+	}
+
+	var IDs []int64
+	for _, deletedGraph := range deletedGraphs {
+		IDs = append(IDs, deletedGraph.getID())
+		if !deleteOptions.DryRun {
+			if err = notifyPreDelete(d.eventer, deletedGraph); err != nil {
+				return err
+			}
+		}
+	}
+
+	if typeOfPrivateNode == reflect.TypeOf(graphs[0]) && len(deletedGraphs) > 0 {
+		if cascadedGraphs, err = d.getCascadedGraphs(ctx, metadata, IDs, deleteOptions); err != nil {
+			return err
+		}
+	}
+
+	result.Deleted = getObjects(append(deletedGraphs, cascadedGraphs...))
+
 	var cypherBuilder graphQueryBuilder
 	if cypherBuilder, err = newCypherBuilder(graphs[0], d.registry, nil); err != nil {
 		return err
 	}
-	cypher, parameter := cypherBuilder.getDeleteAll()
+	cypher, parameters := cypherBuilder.getDeleteAll()
+	if metadata.getSoftDeleteBackendName() != emptyString {
+		cypher, parameters = cypherBuilder.getSoftDeleteAll(getSoftDeleteValue(metadata, deletedAt))
+	}
+	if isFound {
+		//Only the entities found are deleted, not the ones saved since
+		cypher, parameters = getDeleteOfIDs(graphs[0], metadata, IDs, deletedAt)
+	}
+
+	var cascadeDelete string
+	var cascadeDeleteParameters map[string]interface{}
+	if cascadeDelete, cascadeDeleteParameters, err = d.getCascadeDelete(cascadedGraphs, deletedAt); err != nil {
+		return err
+	}
+	parameters = flattenParamters([]map[string]interface{}{parameters, cascadeDeleteParameters})
+
+	if deleteOptions.DryRun {
+		result.Statements = []Statement{{cascadeDelete + cypher, parameters}}
+		return nil
+	}
+
+	for _, cascadedGraph := range cascadedGraphs {
+		if err = notifyPreDelete(d.eventer, cascadedGraph); err != nil {
+			return err
+		}
+	}
 
 	if cypher != emptyString {
-		if records, err = neo4j.Collect(d.cypherExecuter.exec(cypher, parameter)); err != nil {
+		if records, err = result.Counters.collect(d.cypherExecuter.execContext(ctx, cascadeDelete+cypher, parameters)); err != nil {
 			return err
 		}
 		for _, record := range records {
 			graphs[0].setID(record.GetByIndex(0).(int64))
-			deletedGraphs, updatedGraphs := d.store.delete(graphs[0])
-			for _, updatedGraph := range updatedGraphs {
-				notifyPostDelete(d.eventer, updatedGraph, UPDATE)
+			if stored := d.store.get(graphs[0]); stored != nil {
+				if err = d.unloadSoftDeleted(stored, deletedAt); err != nil {
+					return err
+				}
 			}
-			for _, deletedGraph := range deletedGraphs {
-				notifyPostDelete(d.eventer, deletedGraph, DELETE)
+			d.deleteFromStore(graphs[0])
+		}
+		for _, cascadedGraph := range cascadedGraphs {
+			if err = d.unloadSoftDeleted(cascadedGraph, deletedAt); err != nil {
+				return err
 			}
+			d.deleteFromStore(cascadedGraph)
 		}
 	}
 
 	return nil
 }
 
-func (d *deleter) purgeDatabase() error {
+//deleteWhere deletes the entities of the type of object matching filter, and returns how many were deleted.
+//Entities with a soft delete property are soft deleted
+func (d *deleter) deleteWhere(ctx context.Context, object interface{}, filter *Filter) (int64, error) {
+	var (
+		graphs    []graph
+		metadata  metadata
+		records   []neo4j.Record
+		err       error
+		deletedAt = time.Now()
+	)
+
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if graphs, err = d.graphFactory.get(reflect.ValueOf(object), map[int]bool{labels: true}); err != nil {
+		return 0, err
+	}
+	if metadata, err = d.registry.get(graphs[0].getValue().Type()); err != nil {
+		return 0, err
+	}
+
+	ref, match, parameters, err := getWhereMatch(graphs[0], metadata, d.registry, filter)
+	if err != nil {
+		return 0, err
+	}
+	delete := `DETACH DELETE ` + ref + `
+	`
+	if typeOfPrivateRelationship == reflect.TypeOf(graphs[0]) {
+		delete = `DELETE ` + ref + `
+	`
+	}
+	if softDeleteBackendName := metadata.getSoftDeleteBackendName(); softDeleteBackendName != emptyString {
+		delete = `SET ` + ref + `.` + softDeleteBackendName + ` = $softDelete
+	`
+		parameters["softDelete"] = getSoftDeleteValue(metadata, deletedAt)
+	}
+
+	if isDeleteVetoable(d.eventer, metadata) {
+		var matchedGraphs []graph
+		if matchedGraphs, err = getMatchedGraphs(ctx, d.cypherExecuter, d.store, metadata, match+`RETURN `+ref, parameters); err != nil {
+			return 0, err
+		}
+		for _, matchedGraph := range matchedGraphs {
+			if err = notifyPreDelete(d.eventer, matchedGraph); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if records, err = neo4j.Collect(d.cypherExecuter.execContext(ctx, match+delete+`RETURN ID(`+ref+`)`, parameters)); err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		graphs[0].setID(record.GetByIndex(0).(int64))
+		if stored := d.store.get(graphs[0]); stored != nil {
+			if err = d.unloadSoftDeleted(stored, deletedAt); err != nil {
+				return 0, err
+			}
+			d.deleteFromStore(stored)
+		}
+	}
+
+	return int64(len(records)), nil
+}
+
+//getDeleteOfIDs returns the cypher deleting the entities of the type of g with IDs. Entities with a soft delete
+//property are soft deleted at deletedAt
+func getDeleteOfIDs(g graph, metadata metadata, IDs []int64, deletedAt time.Time) (string, map[string]interface{}) {
+	var (
+		ref        = "n"
+		match      = `MATCH (n:` + g.getLabel() + `)`
+		delete     = `DETACH DELETE n`
+		parameters = map[string]interface{}{"ids": IDs}
+	)
+	if typeOfPrivateRelationship == reflect.TypeOf(g) {
+		ref = "r"
+		match = `MATCH ()-[r:` + g.getLabel() + `]->()`
+		delete = `DELETE r`
+	}
+	if softDeleteBackendName := metadata.getSoftDeleteBackendName(); softDeleteBackendName != emptyString {
+		delete = `SET ` + ref + `.` + softDeleteBackendName + ` = $softDelete`
+		parameters["softDelete"] = getSoftDeleteValue(metadata, deletedAt)
+	}
+	return match + ` WHERE ID(` + ref + `) IN $ids ` + delete + ` RETURN ID(` + ref + `)`, parameters
+}
+
+//getDeletedGraphs returns the graphs of the entities of the type of g in the database
+func (d *deleter) getDeletedGraphs(ctx context.Context, g graph, metadata metadata) ([]graph, error) {
+	var (
+		ref   = "n"
+		match = `MATCH (n:` + g.getLabel() + `)`
+	)
+	if typeOfPrivateRelationship == reflect.TypeOf(g) {
+		ref = "r"
+		match = `MATCH ()-[r:` + g.getLabel() + `]->()`
+	}
+	//Soft deleted entities aren't deleted again
+	cypher := match + ` RETURN ` + ref
+	if predicate := getNotSoftDeletedPredicate(ref, metadata); predicate != emptyString {
+		cypher = match + ` WHERE ` + predicate + ` RETURN ` + ref
+	}
+	return getMatchedGraphs(ctx, d.cypherExecuter, d.store, metadata, cypher, nil)
+}
+
+//getMatchedGraphs returns the graphs of the entities of metadata returned by cypher
+func getMatchedGraphs(ctx context.Context, cypherExecuter *cypherExecuter, s store, metadata metadata, cypher string, parameters map[string]interface{}) ([]graph, error) {
+	var (
+		matchedGraphs []graph
+		records       []neo4j.Record
+		err           error
+	)
+	if records, err = neo4j.Collect(cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		switch entity := record.GetByIndex(0).(type) {
+		case neo4j.Node:
+			matchedGraphs = append(matchedGraphs, getDatabaseGraph(s, metadata, entity.Id(), entity.Props()))
+		case neo4j.Relationship:
+			matchedGraphs = append(matchedGraphs, getDatabaseGraph(s, metadata, entity.Id(), entity.Props()))
+		}
+	}
+	return matchedGraphs, nil
+}
+
+//isCascadeDeleted tells whether deleting entities of metadata deletes related nodes with them
+func isCascadeDeleted(metadata metadata, deleteOptions *DeleteOptions) (bool, error) {
+	nodeMetadata, isNodeMetadata := metadata.(*nodeMetadata)
+	if !isNodeMetadata || deleteOptions.Depth < 0 {
+		return false, nil
+	}
+	relationships, _, err := nodeMetadata.getCascadeRelationships()
+	return len(relationships) > 0, err
+}
+
+//getCascadedGraphs returns the nodes deleted with the nodes of IDs, through relationship fields tagged 'cascade:delete',
+//up to the depth of deleteOptions
+func (d *deleter) getCascadedGraphs(ctx context.Context, rootMetadata metadata, IDs []int64, deleteOptions *DeleteOptions) ([]graph, error) {
+	var (
+		cascadedGraphs []graph
+		deletedIDs     = append([]int64{}, IDs...)
+		deleted        = map[int64]bool{}
+		frontier       = map[metadata][]int64{rootMetadata: IDs}
+
+		//frontierMetadatas are the metadatas of the frontier, in the order they were reached
+		frontierMetadatas = []metadata{rootMetadata}
+	)
+	for _, ID := range IDs {
+		deleted[ID] = true
+	}
+
+	for depth := 0; len(frontier) > 0 && (deleteOptions.Depth == 0 || depth < deleteOptions.Depth); depth++ {
+		next := map[metadata][]int64{}
+		var nextMetadatas []metadata
+		for _, frontierMetadata := range frontierMetadatas {
+			frontierIDs := frontier[frontierMetadata]
+			nodeMetadata, isNodeMetadata := frontierMetadata.(*nodeMetadata)
+			if !isNodeMetadata {
+				continue
+			}
+			relationships, relatedMetadatas, err := nodeMetadata.getCascadeRelationships()
+			if err != nil {
+				return nil, err
+			}
+			for index, relationship := range relationships {
+				cypher := `MATCH (n)` + relationship + `(m:` + relatedMetadatas[index].getStructLabel() + `)
+	WHERE ID(n) IN $ids AND NOT ID(m) IN $deletedIDs
+	`
+				if predicate := getNotSoftDeletedPredicate("m", relatedMetadatas[index]); predicate != emptyString {
+					cypher += `AND ` + predicate + `
+	`
+				}
+				if deleteOptions.RemoveOrphans {
+					//Orphans are only related to deleted nodes through the cascaded relationship
+					cypher += `AND all(ownerID IN [(o)` + relationship + `(m) | ID(o)] WHERE ownerID IN $deletedIDs)
+	`
+				}
+				cypher += `RETURN DISTINCT m`
+
+				records, err := neo4j.Collect(d.cypherExecuter.execContext(ctx, cypher, map[string]interface{}{"ids": frontierIDs, "deletedIDs": deletedIDs}))
+				if err != nil {
+					return nil, err
+				}
+				for _, record := range records {
+					neo4jNode := record.GetByIndex(0).(neo4j.Node)
+					if deleted[neo4jNode.Id()] {
+						continue
+					}
+					deleted[neo4jNode.Id()] = true
+					deletedIDs = append(deletedIDs, neo4jNode.Id())
+					if next[relatedMetadatas[index]] == nil {
+						nextMetadatas = append(nextMetadatas, relatedMetadatas[index])
+					}
+					next[relatedMetadatas[index]] = append(next[relatedMetadatas[index]], neo4jNode.Id())
+					cascadedGraphs = append(cascadedGraphs, getDatabaseGraph(d.store, relatedMetadatas[index], neo4jNode.Id(), neo4jNode.Props()))
+				}
+			}
+		}
+		frontier, frontierMetadatas = next, nextMetadatas
+	}
+
+	return cascadedGraphs, nil
+}
+
+//getDatabaseGraph returns the graph of the database entity with ID. It's the stored graph when the entity is loaded in
+//the session, otherwise a new graph of the type of metadata with properties
+func getDatabaseGraph(s store, metadata metadata, ID int64, properties map[string]interface{}) graph {
+	var g graph
+	if _, isNodeMetadata := metadata.(*nodeMetadata); isNodeMetadata {
+		if stored := s.node(ID); stored != nil {
+			return stored
+		}
+		g = &node{ID: ID, relationships: map[int64]graph{}}
+	} else {
+		if stored := s.relationship(ID); stored != nil {
+			return stored
+		}
+		g = &relationship{ID: ID, nodes: map[int64]graph{}}
+	}
+	value := reflect.New(metadata.getType().Elem())
+	g.setValue(&value)
+	g.setProperties(properties)
+	driverPropertiesAsStructFieldValues(g.getProperties(), metadata.getPropertyStructFields())
+	unloadGraphProperties(g, metadata.getPropertyStructFields())
+	unloadGraphID(g, &ID)
+	return g
+}
+
+//deleteFromStore removes g from the store and notifies the graphs deleted and updated with it
+func (d *deleter) deleteFromStore(g graph) {
+	if d.isSoftDeleted(g) {
+		//Soft deleted entities are still in the database with their relationships. They stay in the store to be restored
+		if stored := d.store.get(g); stored != nil {
+			ID := stored.getID()
+			notifyPostDelete(d.eventer, d.cypherExecuter.transaction, stored, DELETE)
+			stored.setID(ID)
+			unloadGraphID(stored, &ID)
+		}
+		return
+	}
+	deletedGraphs, updatedGraphs := d.store.delete(g)
+	for _, updatedGraph := range updatedGraphs {
+		notifyPostDelete(d.eventer, d.cypherExecuter.transaction, updatedGraph, UPDATE)
+	}
+	for _, deletedGraph := range deletedGraphs {
+		notifyPostDelete(d.eventer, d.cypherExecuter.transaction, deletedGraph, DELETE)
+	}
+}
+
+//isSoftDeleted tells whether g is soft deleted instead of being deleted from the database
+func (d *deleter) isSoftDeleted(g graph) bool {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return false
+	}
+	metadata, err := d.registry.get(g.getValue().Type())
+	return err == nil && metadata.getSoftDeleteBackendName() != emptyString
+}
+
+//getCascadeDelete returns the clauses deleting cascadedGraphs, run before the clauses deleting the entities.
+//Cascaded entities with a soft delete property are soft deleted at deletedAt
+func (d *deleter) getCascadeDelete(cascadedGraphs []graph, deletedAt time.Time) (string, map[string]interface{}, error) {
+	var (
+		cypher              string
+		parameters          = map[string]interface{}{}
+		cascadedIDs         []int64
+		softDeleteMetadatas []metadata
+		softDeletedIDs      = map[metadata][]int64{}
+	)
+	for _, cascadedGraph := range cascadedGraphs {
+		metadata, err := d.registry.get(cascadedGraph.getValue().Type())
+		if err != nil {
+			return emptyString, nil, err
+		}
+		if metadata.getSoftDeleteBackendName() == emptyString {
+			cascadedIDs = append(cascadedIDs, cascadedGraph.getID())
+			continue
+		}
+		if softDeletedIDs[metadata] == nil {
+			softDeleteMetadatas = append(softDeleteMetadatas, metadata)
+		}
+		softDeletedIDs[metadata] = append(softDeletedIDs[metadata], cascadedGraph.getID())
+	}
+
+	if len(cascadedIDs) > 0 {
+		cypher += `MATCH (cascaded) WHERE ID(cascaded) IN $cascadedIDs
+	DETACH DELETE cascaded
+	WITH count(*) AS cascadedCount
+	`
+		parameters["cascadedIDs"] = cascadedIDs
+	}
+	for index, metadata := range softDeleteMetadatas {
+		ref := "softDeleted" + strconv.Itoa(index)
+		cypher += `MATCH (` + ref + `) WHERE ID(` + ref + `) IN $` + ref + `IDs
+	SET ` + ref + `.` + metadata.getSoftDeleteBackendName() + ` = $` + ref + `Value
+	WITH count(*) AS ` + ref + `Count
+	`
+		parameters[ref+"IDs"] = softDeletedIDs[metadata]
+		parameters[ref+"Value"] = getSoftDeleteValue(metadata, deletedAt)
+	}
+	return cypher, parameters, nil
+}
+
+//unloadSoftDeleted sets the soft delete field of the domain object of g when g was soft deleted at deletedAt
+func (d *deleter) unloadSoftDeleted(g graph, deletedAt time.Time) error {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return nil
+	}
+	metadata, err := d.registry.get(g.getValue().Type())
+	if err != nil {
+		return err
+	}
+	if metadata.getSoftDeleteBackendName() != emptyString {
+		unloadGraphSoftDelete(g, metadata, getSoftDeleteValue(metadata, deletedAt))
+	}
+	return nil
+}
+
+//restore clears the soft delete property of the soft deleted object
+func (d *deleter) restore(ctx context.Context, object interface{}) error {
+	var (
+		graphs   []graph
+		metadata metadata
+		records  []neo4j.Record
+		IDer     = getIDer(nil, nil)
+		err      error
+	)
+
+	if graphs, err = d.graphFactory.get(reflect.ValueOf(object), map[int]bool{labels: true}); err != nil {
+		return err
+	}
+	IDer(graphs[0])
+	if graphs[0].getID() < 0 {
+		return errors.New("Only entities saved in the database can be restored")
+	}
+	if metadata, err = d.registry.get(graphs[0].getValue().Type()); err != nil {
+		return err
+	}
+	if metadata.getSoftDeleteBackendName() == emptyString {
+		return errors.New("Entities of type " + graphs[0].getValue().Type().String() + " can't be restored. No field is tagged 'softdelete'")
+	}
+
+	cypher := `MATCH (n) WHERE ID(n) = $id SET n.` + metadata.getSoftDeleteBackendName() + ` = $restoreValue RETURN ID(n)`
+	if typeOfPrivateRelationship == reflect.TypeOf(graphs[0]) {
+		cypher = `MATCH ()-[n]->() WHERE ID(n) = $id SET n.` + metadata.getSoftDeleteBackendName() + ` = $restoreValue RETURN ID(n)`
+	}
+	if records, err = neo4j.Collect(d.cypherExecuter.execContext(ctx, cypher, map[string]interface{}{"id": graphs[0].getID(), "restoreValue": getRestoreValue(metadata)})); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("Entity to restore wasn't found in the database")
+	}
+	unloadGraphSoftDelete(graphs[0], metadata, getRestoreValue(metadata))
+	if stored := d.store.get(graphs[0]); stored != nil {
+		unloadGraphSoftDelete(stored, metadata, getRestoreValue(metadata))
+	}
+	return nil
+}
+
+//getObjects returns the runtime objects of graphs
+func getObjects(graphs []graph) []interface{} {
+	var objects []interface{}
+	for _, g := range graphs {
+		if g.getValue() != nil && g.getValue().IsValid() {
+			objects = append(objects, g.getValue().Interface())
+		}
+	}
+	return objects
+}
+
+func (d *deleter) purgeDatabase(ctx context.Context) error {
 	var err error
-	if _, err := d.cypherExecuter.exec("MATCH (n) DETACH DELETE n", nil); err != nil {
+	if _, err := d.cypherExecuter.execContext(ctx, "MATCH (n) DETACH DELETE n", nil); err != nil {
 		return err
 	}
 	for _, deletedGraph := range d.store.purge() {
-		notifyPostDelete(d.eventer, deletedGraph, DELETE)
+		notifyPostDelete(d.eventer, d.cypherExecuter.transaction, deletedGraph, DELETE)
 	}
 
 	return err
@@ -68,17 +68,74 @@ type EventListener interface {
 	OnPostDelete(event Event)
 }
 
+//VetoingEventListener listens for Events like EventListener. A non nil error returned from OnPreSave or OnPreDelete
+//stops the save or delete before anything is sent to the database, and is returned to the caller. DeleteAll, DeleteWhere
+//and UpdateWhere notify the entities they match, as they are before the change
+type VetoingEventListener interface {
+	OnPreSave(event Event) error
+	OnPostSave(event Event)
+	OnPostLoad(event Event)
+	OnPreDelete(event Event) error
+	OnPostDelete(event Event)
+}
+
+//TransactionListener is an EventListener or a VetoingEventListener notified at the end of transactions. OnPostSave and OnPostDelete events
+//raised in a transaction are delivered when the transaction is committed, between OnBeforeCommit and OnAfterCommit,
+//and dropped when it is rolled back
+type TransactionListener interface {
+	OnBeforeCommit()
+	OnAfterCommit()
+	OnAfterRollback()
+}
+
+//BeforeSaver is implemented by domain objects notified before they are saved. A non nil error returned
+//from BeforeSave stops the save before anything is sent to the database, and is returned to the caller
+type BeforeSaver interface {
+	BeforeSave() error
+}
+
+//AfterSaver is implemented by domain objects notified after they are saved, along with OnPostSave
+type AfterSaver interface {
+	AfterSave()
+}
+
+//AfterLoader is implemented by domain objects notified after they are loaded, along with OnPostLoad
+type AfterLoader interface {
+	AfterLoad()
+}
+
+//BeforeDeleter is implemented by domain objects notified before they are deleted. A non nil error returned
+//from BeforeDelete stops the delete before anything is sent to the database, and is returned to the caller
+type BeforeDeleter interface {
+	BeforeDelete() error
+}
+
+//nonVetoingEventListener adapts an EventListener to a VetoingEventListener that never vetoes
+type nonVetoingEventListener struct {
+	EventListener
+}
+
+func (l nonVetoingEventListener) OnPreSave(event Event) error {
+	l.EventListener.OnPreSave(event)
+	return nil
+}
+
+func (l nonVetoingEventListener) OnPreDelete(event Event) error {
+	l.EventListener.OnPreDelete(event)
+	return nil
+}
+
 type eventer struct {
-	eventListeners map[reflect.Value]EventListener
+	eventListeners map[reflect.Value]VetoingEventListener
 }
 
 func newEventer() *eventer {
 	return &eventer{
-		eventListeners: map[reflect.Value]EventListener{}}
+		eventListeners: map[reflect.Value]VetoingEventListener{}}
 }
 
 func (e *eventer) registerEventListener(eventListener EventListener) error {
-	e.eventListeners[reflect.ValueOf(eventListener)] = eventListener
+	e.eventListeners[reflect.ValueOf(eventListener)] = nonVetoingEventListener{eventListener}
 	return nil
 }
 
@@ -86,3 +143,33 @@ func (e *eventer) disposeEventListener(eventListener EventListener) error {
 	delete(e.eventListeners, reflect.ValueOf(eventListener))
 	return nil
 }
+
+func (e *eventer) registerVetoingEventListener(eventListener VetoingEventListener) error {
+	e.eventListeners[reflect.ValueOf(eventListener)] = eventListener
+	return nil
+}
+
+func (e *eventer) disposeVetoingEventListener(eventListener VetoingEventListener) error {
+	delete(e.eventListeners, reflect.ValueOf(eventListener))
+	return nil
+}
+
+//hasVetoingEventListener tells whether an event listener registered with registerVetoingEventListener is listening
+func (e *eventer) hasVetoingEventListener() bool {
+	for _, eventListener := range e.eventListeners {
+		if _, isNonVetoing := eventListener.(nonVetoingEventListener); !isNonVetoing {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *eventer) transactionListeners() []TransactionListener {
+	var transactionListeners []TransactionListener
+	for value := range e.eventListeners {
+		if transactionListener, isTransactionListener := value.Interface().(TransactionListener); isTransactionListener {
+			transactionListeners = append(transactionListeners, transactionListener)
+		}
+	}
+	return transactionListeners
+}
@@ -0,0 +1,225 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//ComparisonOperator is the operator a Filter uses to compare a property with a value
+type ComparisonOperator int
+
+const (
+	//EQUALS matches properties equal to the value
+	EQUALS ComparisonOperator = iota
+
+	//NOT_EQUALS matches properties not equal to the value
+	NOT_EQUALS
+
+	//GREATER_THAN matches properties greater than the value
+	GREATER_THAN
+
+	//GREATER_THAN_EQUAL matches properties greater than or equal to the value
+	GREATER_THAN_EQUAL
+
+	//LESS_THAN matches properties less than the value
+	LESS_THAN
+
+	//LESS_THAN_EQUAL matches properties less than or equal to the value
+	LESS_THAN_EQUAL
+
+	//STARTING_WITH matches string properties starting with the value
+	STARTING_WITH
+
+	//ENDING_WITH matches string properties ending with the value
+	ENDING_WITH
+
+	//CONTAINING matches string properties containing the value
+	CONTAINING
+
+	//MATCHES matches string properties against the regular expression value
+	MATCHES
+
+	//IN matches properties found in the slice value
+	IN
+
+	//IS_NULL matches missing properties. The value is ignored
+	IS_NULL
+
+	//IS_NOT_NULL matches existing properties. The value is ignored
+	IS_NOT_NULL
+)
+
+var comparisonOperators = map[ComparisonOperator]string{
+	EQUALS:             "=",
+	NOT_EQUALS:         "<>",
+	GREATER_THAN:       ">",
+	GREATER_THAN_EQUAL: ">=",
+	LESS_THAN:          "<",
+	LESS_THAN_EQUAL:    "<=",
+	STARTING_WITH:      "STARTS WITH",
+	ENDING_WITH:        "ENDS WITH",
+	CONTAINING:         "CONTAINS",
+	MATCHES:            "=~",
+	IN:                 "IN",
+	IS_NULL:            "IS NULL",
+	IS_NOT_NULL:        "IS NOT NULL"}
+
+type booleanOperator int
+
+const (
+	andOperator booleanOperator = iota
+	orOperator
+)
+
+var booleanOperators = map[booleanOperator]string{
+	andOperator: " AND ",
+	orOperator:  " OR "}
+
+//Filter is a predicate on the properties of entities to load. Filters are composed with And and Or,
+//and applied to related entities with NewRelatedFilter
+type Filter struct {
+	propertyName    string
+	operator        ComparisonOperator
+	value           interface{}
+	relatedField    string
+	booleanOperator booleanOperator
+	filters         []*Filter
+}
+
+//NewFilter creates a filter comparing the backend property propertyName of an entity with value.
+//The property name 'id' compares the internal ID of the entity
+func NewFilter(propertyName string, operator ComparisonOperator, value interface{}) *Filter {
+	return &Filter{
+		propertyName: propertyName,
+		operator:     operator,
+		value:        value}
+}
+
+//NewRelatedFilter creates a filter that holds when at least one of the entities related through
+//the struct field fieldName satisfies filter. For relationship entities, fieldName is the field
+//tagged startNode or endNode
+func NewRelatedFilter(fieldName string, filter *Filter) *Filter {
+	return &Filter{
+		relatedField: fieldName,
+		filters:      []*Filter{filter}}
+}
+
+//And creates a filter that holds when f and all of filters hold
+func (f *Filter) And(filters ...*Filter) *Filter {
+	return &Filter{
+		booleanOperator: andOperator,
+		filters:         append([]*Filter{f}, filters...)}
+}
+
+//Or creates a filter that holds when f or any of filters hold
+func (f *Filter) Or(filters ...*Filter) *Filter {
+	return &Filter{
+		booleanOperator: orOperator,
+		filters:         append([]*Filter{f}, filters...)}
+}
+
+//getCypher returns the predicate of f on the entity referenced by ref. Values are added to parameters
+func (f *Filter) getCypher(ref string, metadata metadata, registry *registry, depth int, parameters map[string]interface{}) (string, error) {
+	if f.relatedField != emptyString {
+		return f.getRelatedCypher(ref, metadata, registry, depth, parameters)
+	}
+
+	if f.filters != nil {
+		var predicates []string
+		for _, filter := range f.filters {
+			if filter == nil {
+				continue
+			}
+			predicate, err := filter.getCypher(ref, metadata, registry, depth, parameters)
+			if err != nil {
+				return emptyString, err
+			}
+			predicates = append(predicates, predicate)
+		}
+		if len(predicates) == 0 {
+			return emptyString, errors.New("Filter composition requires at least one filter")
+		}
+		return `(` + strings.Join(predicates, booleanOperators[f.booleanOperator]) + `)`, nil
+	}
+
+	operator, isOperator := comparisonOperators[f.operator]
+	if !isOperator {
+		return emptyString, errors.New("Unknown comparison operator in filter on property '" + f.propertyName + "'")
+	}
+
+	property, err := getPropertyCypher(ref, f.propertyName, metadata)
+	if err != nil {
+		return emptyString, err
+	}
+
+	if f.operator == IS_NULL || f.operator == IS_NOT_NULL {
+		return property + ` ` + operator, nil
+	}
+
+	parameterName := "filter" + strconv.Itoa(len(parameters))
+	parameters[parameterName] = f.value
+	return property + ` ` + operator + ` $` + parameterName, nil
+}
+
+func (f *Filter) getRelatedCypher(ref string, refMetadata metadata, registry *registry, depth int, parameters map[string]interface{}) (string, error) {
+	var (
+		relatedRef      = "related" + strconv.Itoa(depth)
+		relatedMetadata metadata
+		pattern         string
+		err             error
+	)
+
+	switch m := refMetadata.(type) {
+	case *nodeMetadata:
+		if pattern, relatedMetadata, err = m.getRelatedPattern(ref, relatedRef, f.relatedField); err != nil {
+			return emptyString, err
+		}
+	case *relationshipMetadata:
+		for endpoint, structField := range m.endpoints {
+			if structField.Name == f.relatedField {
+				endpointFunction := `startNode(`
+				if endpoint == endNode {
+					endpointFunction = `endNode(`
+				}
+				pattern = `[` + endpointFunction + ref + `)]`
+				if relatedMetadata, err = registry.get(structField.Type); err != nil {
+					return emptyString, err
+				}
+			}
+		}
+	}
+
+	if relatedMetadata == nil {
+		return emptyString, errors.New("Filter field '" + f.relatedField + "' isn't a related entity field of " + refMetadata.getType().String())
+	}
+
+	var predicate string
+	if predicate, err = f.filters[0].getCypher(relatedRef, relatedMetadata, registry, depth+1, parameters); err != nil {
+		return emptyString, err
+	}
+
+	return `ANY(` + relatedRef + ` IN ` + pattern + ` WHERE ` + predicate + `)`, nil
+}
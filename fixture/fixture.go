@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//Package fixture records the statements the OGM runs, with their parameters and results, to a fixture file, and
+//replays them without a database. A Recorder wraps the backend of a session while the session runs against a
+//database. A Replayer serves the recorded results to a session running the same statements
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+const emptyString = ""
+
+//ErrUnexpectedStatement is returned when a replayed session runs a statement, or parameters, other than the next
+//recorded ones
+var ErrUnexpectedStatement = errors.New("Statement isn't the next recorded statement")
+
+var errTransactionClosed = errors.New("Transaction is closed")
+
+type fixtureFile struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+//interaction is a statement run by a session, with its parameters and its result. Error is the error running the
+//statement failed with. ResultError is the error reading its result failed with
+type interaction struct {
+	Statement     string              `json:"statement"`
+	Parameters    map[string]*value   `json:"parameters,omitempty"`
+	Error         string              `json:"error,omitempty"`
+	Keys          []string            `json:"keys,omitempty"`
+	Records       [][]*value          `json:"records,omitempty"`
+	StatementType neo4j.StatementType `json:"statementType,omitempty"`
+	Counters      *counters           `json:"counters,omitempty"`
+	ResultError   string              `json:"resultError,omitempty"`
+}
+
+func newInteraction(cypher string, params map[string]interface{}) (*interaction, error) {
+	parameters, err := encodeMap(params)
+	if err != nil {
+		return nil, err
+	}
+	return &interaction{Statement: cypher, Parameters: parameters}, nil
+}
+
+//record reads all the records of the driver result of the interaction. The returned result serves the records read
+func (i *interaction) record(driverResult neo4j.Result, err error) (neo4j.Result, error) {
+	if err != nil {
+		i.Error = err.Error()
+		return nil, err
+	}
+
+	r := &result{}
+	if r.keys, err = driverResult.Keys(); err != nil {
+		i.Error = err.Error()
+		return nil, err
+	}
+	i.Keys = r.keys
+
+	for driverResult.Next() {
+		driverRecord := driverResult.Record()
+		values := make([]*value, len(driverRecord.Values()))
+		for index, v := range driverRecord.Values() {
+			if values[index], err = encode(v); err != nil {
+				return nil, err
+			}
+		}
+		i.Records = append(i.Records, values)
+		r.records = append(r.records, driverRecord)
+	}
+
+	if r.err = driverResult.Err(); r.err == nil {
+		r.summary, r.err = driverResult.Summary()
+	}
+	if r.err != nil {
+		i.ResultError = r.err.Error()
+		return r, nil
+	}
+	i.StatementType = r.summary.StatementType()
+	i.Counters = newCounters(r.summary.Counters())
+	return r, nil
+}
+
+//replay returns the recorded result of the interaction
+func (i *interaction) replay(params map[string]interface{}) (neo4j.Result, error) {
+	if i.Error != emptyString {
+		return nil, errors.New(i.Error)
+	}
+
+	r := &result{keys: i.Keys}
+	for _, values := range i.Records {
+		decoded := make([]interface{}, len(values))
+		for index, v := range values {
+			var err error
+			if decoded[index], err = v.decode(); err != nil {
+				return nil, err
+			}
+		}
+		r.records = append(r.records, &record{i.Keys, decoded})
+	}
+
+	if i.ResultError != emptyString {
+		r.err = errors.New(i.ResultError)
+		return r, nil
+	}
+	c := i.Counters
+	if c == nil {
+		c = &counters{}
+	}
+	r.summary = &summary{&statement{i.Statement, params}, i.StatementType, c}
+	return r, nil
+}
+
+//matches tells whether cypher and parameters are the statement and the parameters of the interaction
+func (i *interaction) matches(cypher string, parameters map[string]*value) (bool, error) {
+	if cypher != i.Statement {
+		return false, nil
+	}
+	if len(parameters) == 0 || len(i.Parameters) == 0 {
+		return len(parameters) == len(i.Parameters), nil
+	}
+	//Map keys are sorted and raw values compacted when marshalled. Equal parameters are marshalled to equal bytes
+	expected, err := json.Marshal(i.Parameters)
+	if err != nil {
+		return false, err
+	}
+	actual, err := json.Marshal(parameters)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(expected, actual), nil
+}
+
+func readFixture(path string) ([]*interaction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f fixtureFile
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("Unable to read fixture %s. %s", path, err.Error())
+	}
+	return f.Interactions, nil
+}
+
+func writeFixture(path string, interactions []*interaction) error {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	//Statements are kept readable, with their arrows unescaped
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(emptyString, "\t")
+	if err := encoder.Encode(&fixtureFile{interactions}); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data.Bytes(), 0644)
+}
+
+//parametersString returns the JSON of parameters, for error messages
+func parametersString(parameters map[string]*value) string {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
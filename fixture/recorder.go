@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fixture
+
+import (
+	"sync"
+
+	"github.com/codingfinest/neo4j-go-ogm"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//Recorder is a backend recording the statements run in the sessions of another backend. The statements are recorded
+//with their parameters and results in the order they are run, and written to a fixture file on Close
+type Recorder struct {
+	backend      gogm.Backend
+	path         string
+	mu           sync.Mutex
+	interactions []*interaction
+}
+
+//NewRecorder creates a recorder of the statements run in the sessions of backend, to be written to the fixture file at path
+func NewRecorder(backend gogm.Backend, path string) *Recorder {
+	return &Recorder{backend: backend, path: path}
+}
+
+//NewSession opens a session of the recorded backend
+func (r *Recorder) NewSession(config neo4j.SessionConfig) (neo4j.Session, error) {
+	session, err := r.backend.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingSession{session, r}, nil
+}
+
+//Close writes the recorded statements to the fixture file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return writeFixture(r.path, r.interactions)
+}
+
+//record records cypher with params and the result of run, which runs cypher
+func (r *Recorder) record(cypher string, params map[string]interface{}, run func() (neo4j.Result, error)) (neo4j.Result, error) {
+	i, err := newInteraction(cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	result, err := i.record(run())
+	if err != nil && i.Error == emptyString {
+		//The result can't be recorded
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+	return result, err
+}
+
+type recordingSession struct {
+	neo4j.Session
+	recorder *Recorder
+}
+
+func (s *recordingSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	tx, err := s.Session.BeginTransaction(configurers...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTransaction{tx, s.recorder}, nil
+}
+
+func (s *recordingSession) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.Session.ReadTransaction(s.recordingWork(work), configurers...)
+}
+
+func (s *recordingSession) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.Session.WriteTransaction(s.recordingWork(work), configurers...)
+}
+
+func (s *recordingSession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return s.recorder.record(cypher, params, func() (neo4j.Result, error) {
+		return s.Session.Run(cypher, params, configurers...)
+	})
+}
+
+func (s *recordingSession) recordingWork(work neo4j.TransactionWork) neo4j.TransactionWork {
+	return func(tx neo4j.Transaction) (interface{}, error) {
+		return work(&recordingTransaction{tx, s.recorder})
+	}
+}
+
+type recordingTransaction struct {
+	neo4j.Transaction
+	recorder *Recorder
+}
+
+func (t *recordingTransaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	return t.recorder.record(cypher, params, func() (neo4j.Result, error) {
+		return t.Transaction.Run(cypher, params)
+	})
+}
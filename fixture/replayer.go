@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fixture
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//Replayer is a backend serving the results recorded in a fixture file. Its sessions must run the recorded statements,
+//with the recorded parameters, in the recorded order. Any other statement fails with ErrUnexpectedStatement
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []*interaction
+	next         int
+}
+
+//NewReplayer creates a replayer of the statements recorded in the fixture file at path
+func NewReplayer(path string) (*Replayer, error) {
+	interactions, err := readFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{interactions: interactions}, nil
+}
+
+//NewSession opens a session replaying the recorded statements. Transactions always commit
+func (r *Replayer) NewSession(config neo4j.SessionConfig) (neo4j.Session, error) {
+	return &replayingSession{replayer: r}, nil
+}
+
+//Remaining returns the number of recorded statements that haven't been replayed yet
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.interactions) - r.next
+}
+
+func (r *Replayer) replay(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	parameters, err := encodeMap(params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next >= len(r.interactions) {
+		return nil, fmt.Errorf("%w. All the recorded statements were replayed. Got: %s", ErrUnexpectedStatement, cypher)
+	}
+	i := r.interactions[r.next]
+	matches, err := i.matches(cypher, parameters)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		return nil, fmt.Errorf("%w. Expected: %s with %s. Got: %s with %s", ErrUnexpectedStatement, i.Statement, parametersString(i.Parameters), cypher, parametersString(parameters))
+	}
+	r.next++
+	return i.replay(params)
+}
+
+type replayingSession struct {
+	replayer *Replayer
+}
+
+func (s *replayingSession) LastBookmark() string {
+	return emptyString
+}
+
+func (s *replayingSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	return &replayingTransaction{replayer: s.replayer}, nil
+}
+
+func (s *replayingSession) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(work)
+}
+
+func (s *replayingSession) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(work)
+}
+
+func (s *replayingSession) runTransaction(work neo4j.TransactionWork) (interface{}, error) {
+	tx := &replayingTransaction{replayer: s.replayer}
+	defer tx.Close()
+	return work(tx)
+}
+
+func (s *replayingSession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return s.replayer.replay(cypher, params)
+}
+
+func (s *replayingSession) Close() error {
+	return nil
+}
+
+type replayingTransaction struct {
+	replayer *Replayer
+	done     bool
+}
+
+func (t *replayingTransaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	if t.done {
+		return nil, errTransactionClosed
+	}
+	return t.replayer.replay(cypher, params)
+}
+
+func (t *replayingTransaction) Commit() error {
+	return t.Close()
+}
+
+func (t *replayingTransaction) Rollback() error {
+	return t.Close()
+}
+
+func (t *replayingTransaction) Close() error {
+	t.done = true
+	return nil
+}
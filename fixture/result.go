@@ -0,0 +1,315 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fixture
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type node struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+func (n *node) Id() int64 {
+	return n.id
+}
+
+func (n *node) Labels() []string {
+	return n.labels
+}
+
+func (n *node) Props() map[string]interface{} {
+	return n.props
+}
+
+type relationship struct {
+	id      int64
+	startID int64
+	endID   int64
+	relType string
+	props   map[string]interface{}
+}
+
+func (r *relationship) Id() int64 {
+	return r.id
+}
+
+func (r *relationship) StartId() int64 {
+	return r.startID
+}
+
+func (r *relationship) EndId() int64 {
+	return r.endID
+}
+
+func (r *relationship) Type() string {
+	return r.relType
+}
+
+func (r *relationship) Props() map[string]interface{} {
+	return r.props
+}
+
+type path struct {
+	nodes         []neo4j.Node
+	relationships []neo4j.Relationship
+}
+
+func (p *path) Nodes() []neo4j.Node {
+	return p.nodes
+}
+
+func (p *path) Relationships() []neo4j.Relationship {
+	return p.relationships
+}
+
+type record struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *record) Keys() []string {
+	return r.keys
+}
+
+func (r *record) Values() []interface{} {
+	return r.values
+}
+
+func (r *record) Get(key string) (interface{}, bool) {
+	for index, k := range r.keys {
+		if k == key {
+			return r.values[index], true
+		}
+	}
+	return nil, false
+}
+
+func (r *record) GetByIndex(index int) interface{} {
+	if index < 0 || index >= len(r.values) {
+		return nil
+	}
+	return r.values[index]
+}
+
+//result holds the records of a statement. The recorder reads all the records of a driver result into a result,
+//the replayer decodes them from the fixture file
+type result struct {
+	keys     []string
+	records  []neo4j.Record
+	current  neo4j.Record
+	position int
+	summary  neo4j.ResultSummary
+
+	//err is the error the driver result failed with, after its records
+	err error
+}
+
+func (r *result) Keys() ([]string, error) {
+	return r.keys, nil
+}
+
+func (r *result) Next() bool {
+	if r.position >= len(r.records) {
+		r.current = nil
+		return false
+	}
+	r.current = r.records[r.position]
+	r.position++
+	return true
+}
+
+func (r *result) Err() error {
+	if r.position < len(r.records) {
+		return nil
+	}
+	return r.err
+}
+
+func (r *result) Record() neo4j.Record {
+	return r.current
+}
+
+func (r *result) Summary() (neo4j.ResultSummary, error) {
+	r.position = len(r.records)
+	r.current = nil
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.summary, nil
+}
+
+func (r *result) Consume() (neo4j.ResultSummary, error) {
+	return r.Summary()
+}
+
+//summary is the summary of a replayed statement. It only holds what is recorded: the statement, its type and its counters
+type summary struct {
+	statement     *statement
+	statementType neo4j.StatementType
+	counters      *counters
+}
+
+func (s *summary) Server() neo4j.ServerInfo {
+	return serverInfo{}
+}
+
+func (s *summary) Statement() neo4j.Statement {
+	return s.statement
+}
+
+func (s *summary) StatementType() neo4j.StatementType {
+	return s.statementType
+}
+
+func (s *summary) Counters() neo4j.Counters {
+	return s.counters
+}
+
+func (s *summary) Plan() neo4j.Plan {
+	return nil
+}
+
+func (s *summary) Profile() neo4j.ProfiledPlan {
+	return nil
+}
+
+func (s *summary) Notifications() []neo4j.Notification {
+	return nil
+}
+
+func (s *summary) ResultAvailableAfter() time.Duration {
+	return 0
+}
+
+func (s *summary) ResultConsumedAfter() time.Duration {
+	return 0
+}
+
+type serverInfo struct{}
+
+func (serverInfo) Address() string {
+	return "fixture"
+}
+
+func (serverInfo) Version() string {
+	return "fixture"
+}
+
+type statement struct {
+	text   string
+	params map[string]interface{}
+}
+
+func (s *statement) Text() string {
+	return s.text
+}
+
+func (s *statement) Params() map[string]interface{} {
+	return s.params
+}
+
+//counters are the counters of a statement summary, as written in fixture files
+type counters struct {
+	NodesCreatedCount         int `json:"nodesCreated,omitempty"`
+	NodesDeletedCount         int `json:"nodesDeleted,omitempty"`
+	RelationshipsCreatedCount int `json:"relationshipsCreated,omitempty"`
+	RelationshipsDeletedCount int `json:"relationshipsDeleted,omitempty"`
+	PropertiesSetCount        int `json:"propertiesSet,omitempty"`
+	LabelsAddedCount          int `json:"labelsAdded,omitempty"`
+	LabelsRemovedCount        int `json:"labelsRemoved,omitempty"`
+	IndexesAddedCount         int `json:"indexesAdded,omitempty"`
+	IndexesRemovedCount       int `json:"indexesRemoved,omitempty"`
+	ConstraintsAddedCount     int `json:"constraintsAdded,omitempty"`
+	ConstraintsRemovedCount   int `json:"constraintsRemoved,omitempty"`
+}
+
+func newCounters(c neo4j.Counters) *counters {
+	if c == nil {
+		return &counters{}
+	}
+	return &counters{
+		c.NodesCreated(),
+		c.NodesDeleted(),
+		c.RelationshipsCreated(),
+		c.RelationshipsDeleted(),
+		c.PropertiesSet(),
+		c.LabelsAdded(),
+		c.LabelsRemoved(),
+		c.IndexesAdded(),
+		c.IndexesRemoved(),
+		c.ConstraintsAdded(),
+		c.ConstraintsRemoved()}
+}
+
+func (c *counters) ContainsUpdates() bool {
+	return *c != counters{}
+}
+
+func (c *counters) NodesCreated() int {
+	return c.NodesCreatedCount
+}
+
+func (c *counters) NodesDeleted() int {
+	return c.NodesDeletedCount
+}
+
+func (c *counters) RelationshipsCreated() int {
+	return c.RelationshipsCreatedCount
+}
+
+func (c *counters) RelationshipsDeleted() int {
+	return c.RelationshipsDeletedCount
+}
+
+func (c *counters) PropertiesSet() int {
+	return c.PropertiesSetCount
+}
+
+func (c *counters) LabelsAdded() int {
+	return c.LabelsAddedCount
+}
+
+func (c *counters) LabelsRemoved() int {
+	return c.LabelsRemovedCount
+}
+
+func (c *counters) IndexesAdded() int {
+	return c.IndexesAddedCount
+}
+
+func (c *counters) IndexesRemoved() int {
+	return c.IndexesRemovedCount
+}
+
+func (c *counters) ConstraintsAdded() int {
+	return c.ConstraintsAddedCount
+}
+
+func (c *counters) ConstraintsRemoved() int {
+	return c.ConstraintsRemovedCount
+}
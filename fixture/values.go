@@ -0,0 +1,406 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+const (
+	nullType          = "null"
+	booleanType       = "boolean"
+	integerType       = "integer"
+	floatType         = "float"
+	stringType        = "string"
+	bytesType         = "bytes"
+	listType          = "list"
+	mapType           = "map"
+	nodeType          = "node"
+	relationshipType  = "relationship"
+	pathType          = "path"
+	dateType          = "date"
+	timeType          = "time"
+	localTimeType     = "localtime"
+	dateTimeType      = "datetime"
+	localDateTimeType = "localdatetime"
+	durationType      = "duration"
+	pointType         = "point"
+
+	dateLayout          = "2006-01-02"
+	timeLayout          = "15:04:05.999999999Z07:00"
+	localTimeLayout     = "15:04:05.999999999"
+	localDateTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+//value is a parameter or a result value in a fixture file. Type tells how Value is decoded
+type value struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type nodeFixture struct {
+	ID         int64             `json:"id"`
+	Labels     []string          `json:"labels"`
+	Properties map[string]*value `json:"properties"`
+}
+
+type relationshipFixture struct {
+	ID         int64             `json:"id"`
+	Start      int64             `json:"start"`
+	End        int64             `json:"end"`
+	Type       string            `json:"type"`
+	Properties map[string]*value `json:"properties"`
+}
+
+type pathFixture struct {
+	Nodes         []*nodeFixture         `json:"nodes"`
+	Relationships []*relationshipFixture `json:"relationships"`
+}
+
+type durationFixture struct {
+	Months  int64 `json:"months"`
+	Days    int64 `json:"days"`
+	Seconds int64 `json:"seconds"`
+	Nanos   int   `json:"nanos"`
+}
+
+type pointFixture struct {
+	SrID int      `json:"srid"`
+	X    float64  `json:"x"`
+	Y    float64  `json:"y"`
+	Z    *float64 `json:"z,omitempty"`
+}
+
+func newValue(valueType string, v interface{}) (*value, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &value{valueType, raw}, nil
+}
+
+//encode converts a parameter or a result value to a value of a fixture file. Parameters are converted like the
+//driver converts them: integers to int64, floats to float64, slices to lists and maps to maps of strings
+func encode(v interface{}) (*value, error) {
+	switch typed := v.(type) {
+	case nil:
+		return &value{Type: nullType}, nil
+	case neo4j.Node:
+		node, err := encodeNode(typed)
+		if err != nil {
+			return nil, err
+		}
+		return newValue(nodeType, node)
+	case neo4j.Relationship:
+		relationship, err := encodeRelationship(typed)
+		if err != nil {
+			return nil, err
+		}
+		return newValue(relationshipType, relationship)
+	case neo4j.Path:
+		path := &pathFixture{}
+		for _, n := range typed.Nodes() {
+			node, err := encodeNode(n)
+			if err != nil {
+				return nil, err
+			}
+			path.Nodes = append(path.Nodes, node)
+		}
+		for _, r := range typed.Relationships() {
+			relationship, err := encodeRelationship(r)
+			if err != nil {
+				return nil, err
+			}
+			path.Relationships = append(path.Relationships, relationship)
+		}
+		return newValue(pathType, path)
+	case time.Time:
+		return newValue(dateTimeType, typed.Format(time.RFC3339Nano))
+	case neo4j.Date:
+		return newValue(dateType, typed.Time().Format(dateLayout))
+	case neo4j.OffsetTime:
+		return newValue(timeType, typed.Time().Format(timeLayout))
+	case neo4j.LocalTime:
+		return newValue(localTimeType, typed.Time().Format(localTimeLayout))
+	case neo4j.LocalDateTime:
+		return newValue(localDateTimeType, typed.Time().Format(localDateTimeLayout))
+	case neo4j.Duration:
+		return newValue(durationType, &durationFixture{typed.Months(), typed.Days(), typed.Seconds(), typed.Nanos()})
+	case *neo4j.Point:
+		if typed == nil {
+			return &value{Type: nullType}, nil
+		}
+		point := &pointFixture{SrID: typed.SrId(), X: typed.X(), Y: typed.Y()}
+		if z := typed.Z(); !math.IsNaN(z) {
+			point.Z = &z
+		}
+		return newValue(pointType, point)
+	}
+
+	reflected := reflect.ValueOf(v)
+	switch reflected.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if reflected.IsNil() {
+			return &value{Type: nullType}, nil
+		}
+		return encode(reflected.Elem().Interface())
+	case reflect.Bool:
+		return newValue(booleanType, reflected.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return newValue(integerType, reflected.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return newValue(integerType, int64(reflected.Uint()))
+	case reflect.Float32, reflect.Float64:
+		//Floats are kept as strings for NaN and infinities, which JSON numbers can't represent
+		return newValue(floatType, strconv.FormatFloat(reflected.Float(), 'g', -1, 64))
+	case reflect.String:
+		return newValue(stringType, reflected.String())
+	case reflect.Slice, reflect.Array:
+		if reflected.Type().Elem().Kind() == reflect.Uint8 {
+			return newValue(bytesType, reflected.Slice(0, reflected.Len()).Bytes())
+		}
+		list := make([]*value, reflected.Len())
+		for index := range list {
+			item, err := encode(reflected.Index(index).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list[index] = item
+		}
+		return newValue(listType, list)
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String {
+			return nil, errors.New("Unable to record a map with keys of type " + reflected.Type().Key().String())
+		}
+		m := make(map[string]*value, reflected.Len())
+		iterator := reflected.MapRange()
+		for iterator.Next() {
+			item, err := encode(iterator.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[iterator.Key().String()] = item
+		}
+		return newValue(mapType, m)
+	}
+	return nil, errors.New("Unable to record a value of type " + reflected.Type().String())
+}
+
+func encodeMap(m map[string]interface{}) (map[string]*value, error) {
+	var err error
+	encoded := make(map[string]*value, len(m))
+	for key, item := range m {
+		if encoded[key], err = encode(item); err != nil {
+			return nil, err
+		}
+	}
+	return encoded, nil
+}
+
+func encodeNode(n neo4j.Node) (*nodeFixture, error) {
+	properties, err := encodeMap(n.Props())
+	if err != nil {
+		return nil, err
+	}
+	return &nodeFixture{n.Id(), n.Labels(), properties}, nil
+}
+
+func encodeRelationship(r neo4j.Relationship) (*relationshipFixture, error) {
+	properties, err := encodeMap(r.Props())
+	if err != nil {
+		return nil, err
+	}
+	return &relationshipFixture{r.Id(), r.StartId(), r.EndId(), r.Type(), properties}, nil
+}
+
+//decode converts a value of a fixture file to the value the driver returns
+func (v *value) decode() (interface{}, error) {
+	var err error
+	switch v.Type {
+	case nullType:
+		return nil, nil
+	case booleanType:
+		var b bool
+		err = json.Unmarshal(v.Value, &b)
+		return b, err
+	case integerType:
+		var i int64
+		err = json.Unmarshal(v.Value, &i)
+		return i, err
+	case floatType:
+		var s string
+		if err = json.Unmarshal(v.Value, &s); err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(s, 64)
+	case stringType:
+		var s string
+		err = json.Unmarshal(v.Value, &s)
+		return s, err
+	case bytesType:
+		var b []byte
+		err = json.Unmarshal(v.Value, &b)
+		return b, err
+	case listType:
+		var items []*value
+		if err = json.Unmarshal(v.Value, &items); err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, len(items))
+		for index, item := range items {
+			if list[index], err = item.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case mapType:
+		var items map[string]*value
+		if err = json.Unmarshal(v.Value, &items); err != nil {
+			return nil, err
+		}
+		return decodeMap(items)
+	case nodeType:
+		var node nodeFixture
+		if err = json.Unmarshal(v.Value, &node); err != nil {
+			return nil, err
+		}
+		return node.decode()
+	case relationshipType:
+		var relationship relationshipFixture
+		if err = json.Unmarshal(v.Value, &relationship); err != nil {
+			return nil, err
+		}
+		return relationship.decode()
+	case pathType:
+		var path pathFixture
+		if err = json.Unmarshal(v.Value, &path); err != nil {
+			return nil, err
+		}
+		return path.decode()
+	case durationType:
+		var duration durationFixture
+		if err = json.Unmarshal(v.Value, &duration); err != nil {
+			return nil, err
+		}
+		return neo4j.DurationOf(duration.Months, duration.Days, duration.Seconds, duration.Nanos), nil
+	case pointType:
+		var point pointFixture
+		if err = json.Unmarshal(v.Value, &point); err != nil {
+			return nil, err
+		}
+		if point.Z == nil {
+			return neo4j.NewPoint2D(point.SrID, point.X, point.Y), nil
+		}
+		return neo4j.NewPoint3D(point.SrID, point.X, point.Y, *point.Z), nil
+	case dateType, timeType, localTimeType, dateTimeType, localDateTimeType:
+		return v.decodeTemporal()
+	}
+	return nil, errors.New("Unknown value type '" + v.Type + "' in fixture")
+}
+
+func (v *value) decodeTemporal() (interface{}, error) {
+	var (
+		s   string
+		t   time.Time
+		err error
+	)
+	if err = json.Unmarshal(v.Value, &s); err != nil {
+		return nil, err
+	}
+	switch v.Type {
+	case dateType:
+		if t, err = time.Parse(dateLayout, s); err != nil {
+			return nil, err
+		}
+		return neo4j.DateOf(t), nil
+	case timeType:
+		if t, err = time.Parse(timeLayout, s); err != nil {
+			return nil, err
+		}
+		return neo4j.OffsetTimeOf(t), nil
+	case localTimeType:
+		if t, err = time.Parse(localTimeLayout, s); err != nil {
+			return nil, err
+		}
+		return neo4j.LocalTimeOf(t), nil
+	case localDateTimeType:
+		if t, err = time.Parse(localDateTimeLayout, s); err != nil {
+			return nil, err
+		}
+		return neo4j.LocalDateTimeOf(t), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func decodeMap(items map[string]*value) (map[string]interface{}, error) {
+	var err error
+	m := make(map[string]interface{}, len(items))
+	for key, item := range items {
+		if m[key], err = item.decode(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (n *nodeFixture) decode() (*node, error) {
+	properties, err := decodeMap(n.Properties)
+	if err != nil {
+		return nil, err
+	}
+	return &node{n.ID, n.Labels, properties}, nil
+}
+
+func (r *relationshipFixture) decode() (*relationship, error) {
+	properties, err := decodeMap(r.Properties)
+	if err != nil {
+		return nil, err
+	}
+	return &relationship{r.ID, r.Start, r.End, r.Type, properties}, nil
+}
+
+func (p *pathFixture) decode() (*path, error) {
+	decoded := &path{}
+	for _, n := range p.Nodes {
+		node, err := n.decode()
+		if err != nil {
+			return nil, err
+		}
+		decoded.nodes = append(decoded.nodes, node)
+	}
+	for _, r := range p.Relationships {
+		relationship, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+		decoded.relationships = append(decoded.relationships, relationship)
+	}
+	return decoded, nil
+}
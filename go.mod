@@ -3,10 +3,6 @@ module github.com/codingfinest/neo4j-go-ogm
 go 1.13
 
 require (
-	github.com/golang/mock v1.4.1 // indirect
-	github.com/neo4j-drivers/gobolt v1.7.4 // indirect
-	github.com/neo4j/neo4j-go-driver v1.7.4
+	github.com/neo4j/neo4j-go-driver v1.8.3
 	github.com/onsi/gomega v1.9.0
-	github.com/pkg/errors v0.9.1 // indirect
-	github.com/stretchr/testify v1.5.1 // indirect
 )
@@ -31,6 +31,7 @@ import (
 
 const (
 	idPropertyName            = "id"
+	createdPropertyName       = "created"
 	labelsDelim               = ":"
 	emptyString               = ""
 	spaceString               = " "
@@ -41,6 +42,7 @@ const (
 	indexDelim                = ","
 	statementDelim            = ";\n"
 	mapPropDelim              = "."
+	cascadeDelete             = "delete"
 )
 
 const (
@@ -99,8 +101,8 @@ var logLevels = map[LogLevel]neo4j.LogLevel{
 
 //Gogm is an instance of the OGM
 type Gogm struct {
-	config *Config
-	driver neo4j.Driver
+	config  *Config
+	backend Backend
 }
 
 //New creates a new instance of the OGM
@@ -110,8 +112,15 @@ func New(config *Config) *Gogm {
 		nil}
 }
 
-//NewSession creates a new session on an OGM instance
-func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
+//NewSession creates a new session on an OGM instance, using the database of the configuration.
+//The statements of the session wait for the transactions of bookmarks, the last bookmarks of other sessions
+func (g *Gogm) NewSession(isWriteMode bool, bookmarks ...string) (Session, error) {
+	return g.NewSessionFor(g.config.Database, isWriteMode, bookmarks...)
+}
+
+//NewSessionFor creates a new session on an OGM instance, using database. Loads, saves, deletes, queries and the
+//schema statements of the session are run against database
+func (g *Gogm) NewSessionFor(database string, isWriteMode bool, bookmarks ...string) (Session, error) {
 
 	var err error
 	var accessMode neo4j.AccessMode = neo4j.AccessModeRead
@@ -119,13 +128,13 @@ func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
 		accessMode = neo4j.AccessModeWrite
 	}
 
-	if g.driver == nil {
-		if g.driver, err = getDriver(g.config.URI, g.config.Username, g.config.Password, g.config.LogLevel); err != nil {
+	if g.backend == nil {
+		if g.backend, err = getBackend(g.config); err != nil {
 			return nil, err
 		}
 	}
 
-	cypherExecutor := newCypherExecuter(g.driver, accessMode, nil)
+	cypherExecutor := newCypherExecuter(g.backend, accessMode, database, nil, bookmarks...)
 	registry := newRegistry(*cypherExecutor)
 	graphFactory := newGraphFactory(registry)
 	transactioner := newTransactioner(accessMode)
@@ -134,6 +143,7 @@ func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
 	saver := newSaver(cypherExecutor, store, *eventer, registry, *graphFactory)
 	loader := newLoader(cypherExecutor, store, *eventer, registry, *graphFactory, g.config.AllowCyclicRef)
 	deleter := newDeleter(cypherExecutor, store, *eventer, registry, *graphFactory)
+	updater := newUpdater(cypherExecutor, store, *eventer, registry, *graphFactory)
 	queryer := newQueryer(cypherExecutor, *graphFactory, registry)
 
 	return &sessionImpl{
@@ -141,11 +151,12 @@ func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
 		saver,
 		loader,
 		deleter,
+		updater,
 		queryer,
 		transactioner,
 		store,
 		registry,
-		g.driver,
+		g.backend,
 		eventer}, nil
 }
 
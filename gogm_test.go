@@ -23,29 +23,46 @@
 package gogm_test
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 
 	gogm "github.com/codingfinest/neo4j-go-ogm"
+	"github.com/codingfinest/neo4j-go-ogm/fixture"
+	"github.com/codingfinest/neo4j-go-ogm/inmemory"
 	. "github.com/codingfinest/neo4j-go-ogm/tests/models"
 	. "github.com/onsi/gomega"
 )
 
 var config = &gogm.Config{
-	"bolt://localhost:7687",
-	"neo4j",
-	"Pass1234",
-	gogm.DEBUG,
-	true}
+	URI:            "bolt://localhost:7687",
+	Username:       "neo4j",
+	Password:       "Pass1234",
+	LogLevel:       gogm.DEBUG,
+	AllowCyclicRef: true,
+	Backend:        getTestBackend()}
 
 var ogm = gogm.New(config)
 var session, err = ogm.NewSession(true)
 
 const deletedID int64 = -1
 
+//getTestBackend returns the in memory backend when GOGM_TEST_BACKEND is "inmemory". The tests run against the
+//Neo4j database of config otherwise
+func getTestBackend() gogm.Backend {
+	if os.Getenv("GOGM_TEST_BACKEND") == "inmemory" {
+		return inmemory.NewBackend()
+	}
+	return nil
+}
+
 var eventListener = &TestEventListener{}
 
 //Context: when simple node object is saved
@@ -981,28 +998,18 @@ func TestTransactions(t *testing.T) {
 	//Test Rolling back
 	so.Depth = 3
 	g.Expect(session.Save(&n0, so)).NotTo(HaveOccurred())
-	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
+	g.Expect(tx.Rollback()).NotTo(HaveOccurred())
 	g.Expect(tx.Close()).NotTo(HaveOccurred())
 
-	var loadedN0 *Node0
-	lo := gogm.NewLoadOptions()
-	lo.Depth = 0
-	g.Expect(session.Load(&loadedN0, *n0.ID, lo)).NotTo(HaveOccurred())
-
-	g.Expect(n0.Name).To(Equal("0Update"))
-	g.Expect(loadedN0.Name).To(Equal(n0.Name))
-	g.Expect(*loadedN0.N1.N2.N3.ID).To(Equal(*n3.ID), "Store cache still holds state of rolled back transcation")
+	g.Expect(n3.ID).To(BeNil(), "IDs of entities created in a rolled back transaction are reset")
+	g.Expect(n4.ID).To(BeNil(), "IDs of entities created in a rolled back transaction are reset")
+	g.Expect(n0.ID).NotTo(BeNil())
 
 	g.Expect(session.Reload(&n0)).NotTo(HaveOccurred(), "Reload to sycn runtime objects with backend")
 	g.Expect(n0.Name).To(Equal("0"))
-	g.Expect(*n3.ID).To(Equal(deletedID), "n3 gets deleted as it was rolled back. n3 can't ever be saved again. Must create new instance to save")
 
-	//Testing committing
-	n3 = &Node3{}
-	n3.Name = "3"
+	//Testing committing. Entities created in a rolled back transaction can be saved again
 	n0.N1.N2.N3 = n3
-
-	n3.N4 = n4
 	so.Depth = 3
 
 	tx, err = session.BeginTransaction()
@@ -1018,6 +1025,20 @@ func TestTransactions(t *testing.T) {
 	g.Expect(n2.Name).To(Equal("2Update"))
 	g.Expect(*n3.ID).NotTo(Equal(deletedID))
 
+	//Testing rolling back a delete
+	n0ID := *n0.ID
+	tx, err = session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&n0)).NotTo(HaveOccurred())
+	g.Expect(*n0.ID).To(Equal(deletedID))
+	g.Expect(tx.Rollback()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(*n0.ID).To(Equal(n0ID), "IDs of entities deleted in a rolled back transaction are restored")
+
+	var loadedN0 *Node0
+	g.Expect(session.Load(&loadedN0, n0ID, nil)).NotTo(HaveOccurred())
+	g.Expect(*loadedN0.ID).To(Equal(n0ID))
+
 	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
 	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
 }
@@ -1469,3 +1490,1115 @@ func TestMoreThanOneEntityWithSameLabel(t *testing.T) {
 	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
 	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
 }
+
+func TestLoadAllWithFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{}
+	theMatrix.Title = "The Matrix"
+	theMatrix.Released = 1999
+
+	theMatrixReloaded := &Movie{}
+	theMatrixReloaded.Title = "The Matrix Reloaded"
+	theMatrixReloaded.Released = 2003
+
+	johnWick := &Movie{}
+	johnWick.Title = "John Wick"
+	johnWick.Released = 2014
+
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+
+	carrieAnne := &Actor{}
+	carrieAnne.Name = "Carrie-Anne Moss"
+
+	theMatrixReloaded.AddCharacter(&Character{Movie: theMatrixReloaded, Actor: carrieAnne, Name: "Trinity"})
+	johnWick.AddCharacter(&Character{Movie: johnWick, Actor: keanu, Name: "John Wick"})
+
+	movies := []*Movie{theMatrix, theMatrixReloaded, johnWick}
+	g.Expect(session.Save(&movies, nil)).NotTo(HaveOccurred())
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+
+	lo := gogm.NewLoadOptions()
+	lo.Filter = gogm.NewFilter("released", gogm.GREATER_THAN, 1999).And(gogm.NewFilter("title", gogm.STARTING_WITH, "The"))
+	var loadedMovies []*Movie
+	g.Expect(session.LoadAll(&loadedMovies, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(loadedMovies)).To(Equal(1))
+	g.Expect(loadedMovies[0].Title).To(Equal(theMatrixReloaded.Title))
+	g.Expect(len(loadedMovies[0].Characters)).To(Equal(1), "Related entities of filtered entities are loaded")
+
+	lo.Filter = gogm.NewFilter("released", gogm.LESS_THAN, 2000).Or(gogm.NewFilter("title", gogm.EQUALS, johnWick.Title))
+	loadedMovies = nil
+	g.Expect(session.LoadAll(&loadedMovies, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(loadedMovies)).To(Equal(2))
+
+	lo.Filter = gogm.NewRelatedFilter("Characters", gogm.NewRelatedFilter("Actor", gogm.NewFilter("name", gogm.EQUALS, keanu.Name)))
+	loadedMovies = nil
+	g.Expect(session.LoadAll(&loadedMovies, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(loadedMovies)).To(Equal(1))
+	g.Expect(loadedMovies[0].Title).To(Equal(johnWick.Title))
+
+	var loadedMovie *Movie
+	lo.Filter = gogm.NewFilter("released", gogm.GREATER_THAN, 2020)
+	g.Expect(session.Load(&loadedMovie, *theMatrix.ID, lo)).NotTo(HaveOccurred())
+	g.Expect(loadedMovie).To(BeNil())
+
+	lo.Filter = gogm.NewFilter("unknown", gogm.EQUALS, 0)
+	g.Expect(session.LoadAll(&loadedMovies, nil, lo)).To(HaveOccurred(), "Filter properties must be properties of the entity")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestLoadAllWithSortAndPagination(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	var movies []*Movie
+	for i := 0; i < 5; i++ {
+		movie := &Movie{}
+		movie.Title = "Movie " + strconv.Itoa(i)
+		movie.Released = int64(2000 + i%3)
+		movie.AddCharacter(&Character{Movie: movie, Actor: &Actor{}, Name: movie.Title})
+		movies = append(movies, movie)
+	}
+	g.Expect(session.Save(&movies, nil)).NotTo(HaveOccurred())
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+
+	lo := gogm.NewLoadOptions()
+	lo.SortOrders = []gogm.SortOrder{{PropertyName: "released", Direction: gogm.DESC}, {PropertyName: "title", Direction: gogm.ASC}}
+	lo.Pagination = &gogm.Pagination{Skip: 1, Limit: 2}
+
+	var page []*Movie
+	g.Expect(session.LoadAll(&page, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(page)).To(Equal(2))
+	g.Expect(page[0].Title).To(Equal("Movie 1"))
+	g.Expect(page[1].Title).To(Equal("Movie 4"))
+	g.Expect(len(page[0].Characters)).To(Equal(1), "Entities of a page are loaded to depth")
+	g.Expect(len(page[1].Characters)).To(Equal(1), "Entities of a page are loaded to depth")
+
+	lo.Pagination = &gogm.Pagination{Limit: 2, After: []interface{}{page[1].Released, page[1].Title}}
+	var nextPage []*Movie
+	g.Expect(session.LoadAll(&nextPage, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(nextPage)).To(Equal(2))
+	g.Expect(nextPage[0].Title).To(Equal("Movie 0"))
+	g.Expect(nextPage[1].Title).To(Equal("Movie 3"))
+
+	lo.Pagination = &gogm.Pagination{After: []interface{}{page[1].Released}}
+	g.Expect(session.LoadAll(&nextPage, nil, lo)).To(HaveOccurred(), "Keyset pagination requires a value for every sort order")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	simpleNode := &SimpleNode{}
+	g.Expect(session.SaveCtx(canceled, &simpleNode, nil)).To(Equal(context.Canceled))
+	g.Expect(simpleNode.ID).To(BeNil(), "Nothing is saved with a done context")
+
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelTimeout()
+	g.Expect(session.SaveCtx(ctx, &simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(simpleNode.ID).NotTo(BeNil())
+
+	var loaded *SimpleNode
+	g.Expect(session.LoadCtx(canceled, &loaded, *simpleNode.ID, nil)).To(Equal(context.Canceled))
+	g.Expect(session.LoadCtx(ctx, &loaded, *simpleNode.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loaded).To(Equal(simpleNode))
+
+	_, err := session.CountCtx(canceled, "MATCH (n) RETURN COUNT(n)", nil)
+	g.Expect(err).To(Equal(context.Canceled))
+	g.Expect(session.DeleteCtx(canceled, &simpleNode)).To(Equal(context.Canceled))
+	g.Expect(session.DeleteCtx(ctx, &simpleNode)).NotTo(HaveOccurred())
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	_, err = session.CountCtx(expired, "MATCH (n) RETURN COUNT(n)", nil)
+	g.Expect(err).To(Equal(context.DeadlineExceeded))
+
+	g.Expect(session.PurgeDatabaseCtx(ctx)).NotTo(HaveOccurred())
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	//Committed on success
+	var committed *SimpleNode
+	g.Expect(session.ExecuteWrite(func(tx gogm.Session) error {
+		committed = &SimpleNode{}
+		committed.Prop1 = "committed"
+		return tx.Save(&committed, nil)
+	})).NotTo(HaveOccurred())
+	g.Expect(session.GetTransaction()).To(BeNil())
+
+	var count int64
+	g.Expect(session.ExecuteRead(func(tx gogm.Session) error {
+		var err error
+		count, err = tx.CountEntitiesOfType(&committed)
+		return err
+	})).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	//Rolled back on error
+	workErr := errors.New("work failed")
+	attempts := 0
+	g.Expect(session.ExecuteWrite(func(tx gogm.Session) error {
+		attempts++
+		rolledBack := &SimpleNode{}
+		if err := tx.Save(&rolledBack, nil); err != nil {
+			return err
+		}
+		return workErr
+	})).To(Equal(workErr))
+	g.Expect(attempts).To(Equal(1), "Non transient errors are not retried")
+	g.Expect(session.GetTransaction()).To(BeNil())
+
+	//Rolled back on panic
+	g.Expect(func() {
+		session.ExecuteWrite(func(tx gogm.Session) error {
+			rolledBack := &SimpleNode{}
+			tx.Save(&rolledBack, nil)
+			panic(workErr)
+		})
+	}).To(Panic())
+	g.Expect(session.GetTransaction()).To(BeNil())
+
+	count, err := session.CountEntitiesOfType(&committed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestTransactionEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	transactionListener := &TestTransactionListener{}
+	g.Expect(session.RegisterEventListener(transactionListener)).NotTo(HaveOccurred())
+
+	//Events outside transactions are delivered immediately
+	simpleNode := &SimpleNode{}
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(transactionListener.Notices).To(Equal([]string{"OnPostSave"}))
+
+	//Events of rolled back transactions are dropped
+	transactionListener.Notices = nil
+	tx, err := session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	simpleNode.Prop1 = "rolled back"
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&simpleNode)).NotTo(HaveOccurred())
+	g.Expect(transactionListener.Notices).To(BeEmpty())
+	g.Expect(tx.Rollback()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(transactionListener.Notices).To(Equal([]string{"OnAfterRollback"}))
+
+	//Events of committed transactions are delivered on commit
+	transactionListener.Notices = nil
+	tx, err = session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	simpleNode.Prop1 = "committed"
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&simpleNode)).NotTo(HaveOccurred())
+	g.Expect(transactionListener.Notices).To(BeEmpty())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(transactionListener.Notices).To(Equal([]string{"OnBeforeCommit", "OnPostSave", "OnPostDelete", "OnAfterCommit"}))
+
+	g.Expect(session.DisposeEventListener(transactionListener)).NotTo(HaveOccurred())
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestVetoingEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	veto := errors.New("vetoed")
+	vetoingEventListener := &TestVetoingEventListener{Err: veto}
+	g.Expect(session.RegisterVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+
+	simpleNode := &SimpleNode{}
+	g.Expect(session.Save(&simpleNode, nil)).To(Equal(veto))
+	count, err := session.CountEntitiesOfType(&simpleNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero(), "Vetoed saves don't reach the database")
+
+	vetoingEventListener.Err = nil
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(*simpleNode.ID > -1).To(BeTrue())
+
+	vetoingEventListener.Err = veto
+	g.Expect(session.Delete(&simpleNode)).To(Equal(veto))
+	g.Expect(*simpleNode.ID).NotTo(Equal(deletedID))
+	count, err = session.CountEntitiesOfType(&simpleNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)), "Vetoed deletes don't reach the database")
+
+	g.Expect(session.DeleteAll(&simpleNode, nil)).To(Equal(veto))
+	_, err = session.DeleteWhere(&simpleNode, nil)
+	g.Expect(err).To(Equal(veto))
+	_, err = session.UpdateWhere(&simpleNode, nil, map[string]interface{}{"prop1": "vetoed"})
+	g.Expect(err).To(Equal(veto))
+	count, err = session.Count("MATCH (n:SimpleNode) WHERE n.prop1 <> 'vetoed' RETURN count(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)), "Vetoed bulk updates and deletes don't reach the database")
+
+	//DeleteAll deletes the entities it notified, not the ones saved since
+	otherSession, err := ogm.NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	savedSince := &SimpleNode{}
+	vetoingEventListener.Err = nil
+	vetoingEventListener.PreDelete = func(event gogm.Event) {
+		if savedSince.ID == nil {
+			g.Expect(otherSession.Save(&savedSince, nil)).NotTo(HaveOccurred())
+		}
+	}
+	g.Expect(session.DeleteAll(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(*simpleNode.ID).To(Equal(deletedID))
+	count, err = session.CountEntitiesOfType(&simpleNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.DisposeVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestEntityCallbacks(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	callbackNode := &CallbackNode{}
+	g.Expect(session.Save(&callbackNode, nil)).To(HaveOccurred(), "BeforeSave errors stop saves")
+	count, err := session.CountEntitiesOfType(&callbackNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero())
+
+	callbackNode.Name = "  name  "
+	callbackNode.Callbacks = nil
+	g.Expect(session.Save(&callbackNode, nil)).NotTo(HaveOccurred())
+	g.Expect(callbackNode.Callbacks).To(Equal([]string{"BeforeSave", "AfterSave"}))
+	g.Expect(callbackNode.Name).To(Equal("name"))
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loaded *CallbackNode
+	g.Expect(session.Load(&loaded, *callbackNode.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal("name"), "BeforeSave changes are persisted")
+	g.Expect(loaded.Callbacks).To(Equal([]string{"AfterLoad"}))
+
+	loaded.Callbacks = nil
+	g.Expect(session.Delete(&loaded)).NotTo(HaveOccurred())
+	g.Expect(loaded.Callbacks).To(Equal([]string{"BeforeDelete"}))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	otherSession, err := ogm.NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	from := &VersionedNode{Name: "from"}
+	to := &VersionedNode{Name: "to"}
+	from.Knows = &VersionedRelationship{From: from, To: to, Since: 2000}
+	g.Expect(session.Save(&from, nil)).NotTo(HaveOccurred())
+	g.Expect(from.Version).To(Equal(int64(1)))
+	g.Expect(from.Knows.Version).To(Equal(1))
+
+	//Nodes
+	var otherFrom *VersionedNode
+	g.Expect(otherSession.Load(&otherFrom, *from.ID, nil)).NotTo(HaveOccurred())
+	otherFrom.Name = "other from"
+	g.Expect(otherSession.Save(&otherFrom, nil)).NotTo(HaveOccurred())
+	g.Expect(otherFrom.Version).To(Equal(int64(2)))
+
+	from.Name = "stale from"
+	g.Expect(session.Save(&from, nil)).To(Equal(gogm.ErrOptimisticLock))
+	g.Expect(from.Version).To(Equal(int64(1)))
+
+	g.Expect(session.Reload(&from)).NotTo(HaveOccurred())
+	g.Expect(from.Version).To(Equal(int64(2)))
+	from.Name = "from"
+	g.Expect(session.Save(&from, nil)).NotTo(HaveOccurred())
+	g.Expect(from.Version).To(Equal(int64(3)))
+
+	//Relationships
+	var otherKnows *VersionedRelationship
+	g.Expect(otherSession.Load(&otherKnows, *from.Knows.ID, nil)).NotTo(HaveOccurred())
+	otherKnows.Since = 2010
+	g.Expect(otherSession.Save(&otherKnows, nil)).NotTo(HaveOccurred())
+	g.Expect(otherKnows.Version).To(Equal(2))
+
+	from.Knows.Since = 2020
+	g.Expect(session.Save(&from, nil)).To(Equal(gogm.ErrOptimisticLock))
+
+	g.Expect(session.Reload(&from.Knows)).NotTo(HaveOccurred())
+	g.Expect(from.Knows.Since).To(Equal(int64(2010)))
+	from.Knows.Since = 2020
+	g.Expect(session.Save(&from, nil)).NotTo(HaveOccurred())
+	g.Expect(from.Knows.Version).To(Equal(3))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestSaveWithMerge(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	n9 := &Node9{}
+	n9.TestId = "feed-1"
+	n9.Name = "first"
+	g.Expect(session.Save(&n9, nil)).NotTo(HaveOccurred())
+
+	fromFeed := &Node9{}
+	fromFeed.TestId = "feed-1"
+	fromFeed.Name = "second"
+	g.Expect(session.Save(&fromFeed, nil)).To(HaveOccurred(), "Custom IDs are unique")
+
+	fromFeed = &Node9{}
+	fromFeed.TestId = "feed-1"
+	fromFeed.Name = "second"
+	so := gogm.NewSaveOptions()
+	so.Merge = true
+	g.Expect(session.Save(&fromFeed, so)).NotTo(HaveOccurred())
+	g.Expect(*fromFeed.ID).To(Equal(*n9.ID), "Merged entities get the ID of the existing entity")
+
+	count, err := session.CountEntitiesOfType(&fromFeed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loaded *Node9
+	g.Expect(session.Load(&loaded, "feed-1", nil)).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal("second"))
+
+	newFromFeed := &Node9{}
+	newFromFeed.TestId = "feed-2"
+	newFromFeed.Name = "third"
+	g.Expect(session.Save(&newFromFeed, so)).NotTo(HaveOccurred())
+	g.Expect(*newFromFeed.ID).NotTo(Equal(*n9.ID), "Entities not matched are created")
+
+	ambiguous := &AmbiguousMergeNode{Email: "email", Login: "login"}
+	g.Expect(session.Save(&ambiguous, so)).To(HaveOccurred(), "Entities with several unique properties and no custom ID aren't merged")
+	g.Expect(ambiguous.ID).To(BeNil())
+	g.Expect(session.Save(&ambiguous, nil)).NotTo(HaveOccurred(), "They're created")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestMergeVersioned(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	stored := &VersionedMergeNode{Code: "a", Name: "first"}
+	g.Expect(session.Save(&stored, nil)).NotTo(HaveOccurred())
+	stored.Name = "second"
+	g.Expect(session.Save(&stored, nil)).NotTo(HaveOccurred())
+	g.Expect(stored.Version).To(Equal(int64(2)))
+
+	so := gogm.NewSaveOptions()
+	so.Merge = true
+	so.Result = &gogm.SaveResult{}
+	stale := &VersionedMergeNode{Code: "a", Name: "stale", Version: 1}
+	g.Expect(session.Save(&stale, so)).To(Equal(gogm.ErrOptimisticLock), "Stale merged entities don't move versions back")
+
+	merged := &VersionedMergeNode{Code: "a", Name: "merged", Version: 2}
+	g.Expect(session.Save(&merged, so)).NotTo(HaveOccurred())
+	g.Expect(*merged.ID).To(Equal(*stored.ID))
+	g.Expect(merged.Version).To(Equal(int64(3)))
+	g.Expect(so.Result.Created).To(BeEmpty(), "Merged entities matching an entity are updated")
+	g.Expect(so.Result.Updated).To(ConsistOf(merged))
+
+	created := &VersionedMergeNode{Code: "b", Name: "created"}
+	g.Expect(session.Save(&created, so)).NotTo(HaveOccurred())
+	g.Expect(created.Version).To(Equal(int64(1)))
+	g.Expect(so.Result.Created).To(ConsistOf(created))
+
+	so.BatchSize = 10
+	stale = &VersionedMergeNode{Code: "a", Name: "stale", Version: 2}
+	g.Expect(session.Save(&stale, so)).To(Equal(gogm.ErrOptimisticLock))
+
+	batched := &VersionedMergeNode{Code: "a", Name: "batched", Version: 3}
+	g.Expect(session.Save(&batched, so)).NotTo(HaveOccurred())
+	g.Expect(*batched.ID).To(Equal(*stored.ID))
+	g.Expect(batched.Version).To(Equal(int64(4)))
+	g.Expect(so.Result.Created).To(BeEmpty())
+	g.Expect(so.Result.Updated).To(ConsistOf(batched))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestMergeRelationships(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	m := &MergeNode{Name: "m"}
+	s := &SimpleNode{Prop1: "s"}
+	m.Follows = []*SimpleNode{s}
+	m.Rates = []*Rating{{From: m, To: s, Source: "feed", Stars: 3}}
+	g.Expect(session.Save(&m, nil)).NotTo(HaveOccurred())
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	lo := gogm.NewLoadOptions()
+	lo.Depth = 0
+	var loadedM *MergeNode
+	var loadedS *SimpleNode
+	g.Expect(session.Load(&loadedM, *m.ID, lo)).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loadedS, *s.ID, lo)).NotTo(HaveOccurred())
+
+	sameRating := &Rating{From: loadedM, To: loadedS, Source: "feed", Stars: 5}
+	loadedM.Follows = []*SimpleNode{loadedS}
+	loadedM.Rates = []*Rating{sameRating, {From: loadedM, To: loadedS, Source: "survey", Stars: 1}}
+	g.Expect(session.Save(&loadedM, nil)).NotTo(HaveOccurred())
+	g.Expect(*sameRating.ID).To(Equal(*m.Rates[0].ID), "Merged relationships get the ID of the existing relationship")
+
+	count, err := session.Count("MATCH (:MergeNode)-[r:MergeNode_SimpleNode]->(:SimpleNode) RETURN count(r)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	count, err = session.Count("MATCH (:MergeNode)-[r:RATING]->(:SimpleNode) RETURN count(r)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)), "Relationships are merged on their merge properties")
+
+	var rating *Rating
+	g.Expect(session.Load(&rating, *sameRating.ID, lo)).NotTo(HaveOccurred())
+	g.Expect(rating.Stars).To(Equal(int64(5)))
+
+	endorsement := &Endorsement{From: loadedM, To: loadedS}
+	g.Expect(session.Save(&endorsement, nil)).NotTo(HaveOccurred())
+	sameEndorsement := &Endorsement{From: loadedM, To: loadedS}
+	g.Expect(session.Save(&sameEndorsement, nil)).NotTo(HaveOccurred())
+	g.Expect(*sameEndorsement.ID).To(Equal(*endorsement.ID), "The merge tag of a relationship entity is found on its embedded gogm.Relationship")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestBatchSave(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	so := gogm.NewSaveOptions()
+	so.Depth = 2
+	so.BatchSize = 10
+
+	var simpleNodes []*SimpleNode
+	for i := 0; i < 25; i++ {
+		simpleNodes = append(simpleNodes, &SimpleNode{Prop1: strconv.Itoa(i)})
+	}
+	g.Expect(session.Save(&simpleNodes, so)).NotTo(HaveOccurred())
+
+	IDs := map[int64]bool{}
+	for _, simpleNode := range simpleNodes {
+		g.Expect(simpleNode.ID).NotTo(BeNil())
+		IDs[*simpleNode.ID] = true
+	}
+	g.Expect(len(IDs)).To(Equal(25))
+
+	count, err := session.CountEntitiesOfType(&simpleNodes[0])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(25)))
+
+	m := &MergeNode{Name: "m"}
+	m.Follows = []*SimpleNode{simpleNodes[0], simpleNodes[1]}
+	m.Rates = []*Rating{{From: m, To: simpleNodes[2], Source: "feed", Stars: 3}}
+	simpleNodes[0].Prop1 = "updated"
+	g.Expect(session.Save(&m, so)).NotTo(HaveOccurred())
+	g.Expect(m.ID).NotTo(BeNil())
+	g.Expect(m.Rates[0].ID).NotTo(BeNil())
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loadedM *MergeNode
+	g.Expect(session.Load(&loadedM, *m.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(len(loadedM.Follows)).To(Equal(2))
+	g.Expect(len(loadedM.Rates)).To(Equal(1))
+	g.Expect(*loadedM.Rates[0].ID).To(Equal(*m.Rates[0].ID))
+	g.Expect(loadedM.Rates[0].Stars).To(Equal(int64(3)))
+
+	var updated *SimpleNode
+	g.Expect(session.Load(&updated, *simpleNodes[0].ID, nil)).NotTo(HaveOccurred())
+	g.Expect(updated.Prop1).To(Equal("updated"), "Existing entities are updated along with batches")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestCascadeDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	piece := &SimpleNode{Prop1: "piece"}
+	ownPart := &CascadePart{Name: "own", Pieces: []*SimpleNode{piece}}
+	sharedPart := &CascadePart{Name: "shared"}
+	owner := &CascadeOwner{Name: "owner", Parts: []*CascadePart{ownPart, sharedPart}}
+	otherOwner := &CascadeOwner{Name: "other", Parts: []*CascadePart{sharedPart}}
+	owners := []*CascadeOwner{owner, otherOwner}
+	g.Expect(session.Save(&owners, nil)).NotTo(HaveOccurred())
+
+	count := func(object interface{}) int64 {
+		count, err := session.CountEntitiesOfType(object)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	deleteOptions := &gogm.DeleteOptions{RemoveOrphans: true, DryRun: true, Result: &gogm.DeleteResult{}}
+	g.Expect(session.DeleteWithOptions(&owner, deleteOptions)).NotTo(HaveOccurred())
+	var deleted []string
+	for _, object := range deleteOptions.Result.Deleted {
+		switch o := object.(type) {
+		case *CascadeOwner:
+			deleted = append(deleted, o.Name)
+		case *CascadePart:
+			deleted = append(deleted, o.Name)
+		case *SimpleNode:
+			deleted = append(deleted, o.Prop1)
+		}
+	}
+	g.Expect(deleted).To(ConsistOf("owner", "own", "piece"), "Parts of other owners aren't orphans")
+	g.Expect(count(&ownPart)).To(Equal(int64(2)), "Dry runs don't delete")
+
+	deleteOptions.DryRun = false
+	g.Expect(session.DeleteWithOptions(&owner, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(count(&owner)).To(Equal(int64(1)))
+	g.Expect(count(&ownPart)).To(Equal(int64(1)))
+	g.Expect(count(&piece)).To(Equal(int64(0)))
+
+	otherOwner.Parts[0].Pieces = []*SimpleNode{{Prop1: "kept"}}
+	g.Expect(session.Save(&otherOwner, nil)).NotTo(HaveOccurred())
+	deleteOptions = gogm.NewDeleteOptions()
+	deleteOptions.Depth = 1
+	g.Expect(session.DeleteWithOptions(&otherOwner, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(count(&ownPart)).To(Equal(int64(0)))
+	g.Expect(count(&piece)).To(Equal(int64(1)), "Nodes deeper than Depth aren't deleted")
+
+	deleteOptions = &gogm.DeleteOptions{DryRun: true, Result: &gogm.DeleteResult{}}
+	g.Expect(session.DeleteAll(&piece, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(deleteOptions.Result.Deleted).To(HaveLen(1))
+	deleteOptions.DryRun = false
+	g.Expect(session.DeleteAll(&piece, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(deleteOptions.Result.Deleted).To(BeEmpty(), "Entities which don't cascade aren't found before they're deleted")
+	g.Expect(count(&piece)).To(Equal(int64(0)))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestSoftDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	kept := &SoftDeleteChild{Name: "kept"}
+	removed := &SoftDeleteChild{Name: "removed"}
+	parent := &SoftDeleteNode{Name: "parent", Children: []*SoftDeleteChild{kept, removed}}
+	g.Expect(session.Save(&parent, nil)).NotTo(HaveOccurred())
+
+	listener := &TestTransactionListener{}
+	g.Expect(session.RegisterEventListener(listener)).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&removed)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(listener)).NotTo(HaveOccurred())
+	g.Expect(removed.Deleted).To(BeTrue())
+	g.Expect(listener.Notices).To(Equal([]string{"OnPostDelete"}), "Relationships of soft deleted nodes aren't deleted")
+	g.Expect(session.Save(&parent, nil)).NotTo(HaveOccurred())
+	count, err := session.Count("MATCH (:SoftDeleteNode)-[r]->(:SoftDeleteChild) RETURN count(r)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)))
+	count, err = session.CountEntitiesOfType(&removed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+	count, err = session.Count("MATCH (n:SoftDeleteChild) RETURN count(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)), "Soft deleted entities stay in the database")
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loaded *SoftDeleteNode
+	g.Expect(session.Load(&loaded, *parent.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(len(loaded.Children)).To(Equal(1))
+	g.Expect(loaded.Children[0].Name).To(Equal("kept"))
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	loadOptions := gogm.NewLoadOptions()
+	loadOptions.IncludeSoftDeleted = true
+	g.Expect(session.Load(&loaded, *parent.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(len(loaded.Children)).To(Equal(2))
+
+	g.Expect(session.Restore(&removed)).NotTo(HaveOccurred())
+	g.Expect(removed.Deleted).To(BeFalse())
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loaded, *parent.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(len(loaded.Children)).To(Equal(2))
+
+	g.Expect(session.DeleteAll(&parent, nil)).NotTo(HaveOccurred())
+	var parents []*SoftDeleteNode
+	g.Expect(session.LoadAll(&parents, nil, nil)).NotTo(HaveOccurred())
+	g.Expect(len(parents)).To(Equal(0))
+	g.Expect(session.LoadAll(&parents, nil, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(len(parents)).To(Equal(1))
+	g.Expect(parents[0].RemovedAt).NotTo(BeNil())
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestUpdateAndDeleteWhere(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	var simpleNodeRef *SimpleNode
+	simpleNodes := []*SimpleNode{{Prop1: "a1"}, {Prop1: "a2"}, {Prop1: "b1"}}
+	g.Expect(session.Save(&simpleNodes, nil)).NotTo(HaveOccurred())
+
+	startingWithA := gogm.NewFilter("prop1", gogm.STARTING_WITH, "a")
+	updated, err := session.UpdateWhere(&simpleNodeRef, startingWithA, map[string]interface{}{"prop1": "updated"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated).To(Equal(int64(2)))
+	g.Expect(simpleNodes[0].Prop1).To(Equal("updated"), "Cached objects are updated")
+	g.Expect(simpleNodes[0].UpdatedAt).NotTo(BeZero())
+	g.Expect(simpleNodes[2].Prop1).To(Equal("b1"))
+
+	_, err = session.UpdateWhere(&simpleNodeRef, nil, map[string]interface{}{"unknown": 1})
+	g.Expect(err).To(HaveOccurred())
+
+	deleted, err := session.DeleteWhere(&simpleNodeRef, gogm.NewFilter("prop1", gogm.EQUALS, "updated"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(Equal(int64(2)))
+	g.Expect(*simpleNodes[0].ID).To(Equal(deletedID))
+	count, err := session.CountEntitiesOfType(&simpleNodeRef)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestUpdateProperties(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	versionedNode := &VersionedNode{Name: "name"}
+	g.Expect(session.Save(&versionedNode, nil)).NotTo(HaveOccurred())
+	g.Expect(versionedNode.Version).To(Equal(int64(1)))
+
+	versionedNode.Name = "updated"
+	g.Expect(session.UpdateProperties(&versionedNode, "Name")).NotTo(HaveOccurred())
+	g.Expect(versionedNode.Version).To(Equal(int64(2)), "Partial updates bump versions")
+	g.Expect(session.UpdateProperties(&versionedNode, "Unknown")).To(HaveOccurred())
+
+	g.Expect(session.Save(&versionedNode, nil)).NotTo(HaveOccurred(), "The cached version is refreshed")
+
+	otherSession, err := ogm.NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	var otherVersionedNode *VersionedNode
+	g.Expect(otherSession.Load(&otherVersionedNode, *versionedNode.ID, nil)).NotTo(HaveOccurred())
+	otherVersionedNode.Name = "other"
+	g.Expect(otherSession.UpdateProperties(&otherVersionedNode, "Name")).NotTo(HaveOccurred())
+	versionedNode.Name = "stale"
+	g.Expect(session.UpdateProperties(&versionedNode, "Name")).To(Equal(gogm.ErrOptimisticLock), "Stale partial updates fail")
+	g.Expect(session.UpdatePropertiesByID(&versionedNode, *versionedNode.ID, map[string]interface{}{"name": "stale"})).To(Equal(gogm.ErrOptimisticLock), "The cached version is checked")
+	g.Expect(otherSession.UpdatePropertiesByID(&otherVersionedNode, *versionedNode.ID, map[string]interface{}{"name": "current"})).NotTo(HaveOccurred())
+	g.Expect(session.Reload(&versionedNode)).NotTo(HaveOccurred())
+	g.Expect(versionedNode.Name).To(Equal("current"))
+
+	callbackNode := &CallbackNode{Name: "name"}
+	g.Expect(session.Save(&callbackNode, nil)).NotTo(HaveOccurred())
+	callbackNode.Name = " trimmed "
+	callbackNode.Callbacks = nil
+	g.Expect(session.UpdateProperties(&callbackNode, "Name")).NotTo(HaveOccurred())
+	g.Expect(callbackNode.Callbacks).To(ContainElement("BeforeSave"))
+	g.Expect(callbackNode.Name).To(Equal("trimmed"))
+	callbackNode.Name = ""
+	g.Expect(session.UpdateProperties(&callbackNode, "Name")).To(HaveOccurred(), "BeforeSave stops partial updates")
+
+	veto := errors.New("vetoed")
+	vetoingEventListener := &TestVetoingEventListener{Err: veto}
+	g.Expect(session.RegisterVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+	versionedNode.Name = "vetoed"
+	g.Expect(session.UpdateProperties(&versionedNode, "Name")).To(Equal(veto))
+	g.Expect(session.DisposeVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+
+	n4 := &Node4{}
+	n5 := &Node5{}
+	simpleRelationship := &SimpleRelationship{N5: n5, N4: n4, Name: "name", TestID: "testID"}
+	g.Expect(session.Save(&simpleRelationship, nil)).NotTo(HaveOccurred())
+	var simpleRelationshipRef *SimpleRelationship
+	g.Expect(session.UpdatePropertiesByID(&simpleRelationshipRef, "testID", map[string]interface{}{"name": "updated"})).NotTo(HaveOccurred())
+	g.Expect(simpleRelationship.Name).To(Equal("updated"), "Cached objects are refreshed")
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loaded *SimpleRelationship
+	g.Expect(session.Load(&loaded, "testID", nil)).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal("updated"))
+
+	g.Expect(session.UpdatePropertiesByID(&simpleRelationshipRef, "unknownID", map[string]interface{}{"name": "updated"})).To(MatchError("Entity to update wasn't found in the database"))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestTransactionConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	metadata := map[string]interface{}{"application": "gogm"}
+	tx, err := session.BeginTransaction(neo4j.WithTxTimeout(time.Minute), neo4j.WithTxMetadata(metadata))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.GetTransaction()).To(Equal(tx))
+	g.Expect(tx.Timeout()).To(Equal(time.Minute))
+	g.Expect(tx.Metadata()).To(Equal(metadata))
+	g.Expect(tx.IsOpen()).To(BeTrue())
+
+	simpleNode := &SimpleNode{Prop1: "tx"}
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.IsOpen()).To(BeFalse())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(session.GetTransaction()).To(BeNil())
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestNestedTransactions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	count := func() int64 {
+		var simpleNodeRef *SimpleNode
+		count, err := session.CountEntitiesOfType(&simpleNodeRef)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	//Committed nested transactions are committed with their outer transaction
+	tx, err := session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.ExecuteWrite(func(tx gogm.Session) error {
+		simpleNode := &SimpleNode{Prop1: "nested"}
+		return tx.Save(&simpleNode, nil)
+	})).NotTo(HaveOccurred())
+	g.Expect(session.GetTransaction()).To(Equal(tx), "Nested transactions join their outer transaction")
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(count()).To(Equal(int64(1)))
+
+	//Rolled back nested transactions roll back their outer transaction
+	tx, err = session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	outerNode := &SimpleNode{Prop1: "outer"}
+	g.Expect(session.Save(&outerNode, nil)).NotTo(HaveOccurred())
+	nested, err := session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	simpleNode := &SimpleNode{Prop1: "inner"}
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(tx.Commit()).To(HaveOccurred(), "Nested transactions end before their outer transaction")
+	g.Expect(nested.Rollback()).NotTo(HaveOccurred())
+	g.Expect(simpleNode.ID).To(BeNil(), "The session cache is restored to the start of the nested transaction")
+	g.Expect(tx.Commit()).To(Equal(gogm.ErrRollbackOnly))
+	g.Expect(tx.IsOpen()).To(BeFalse())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(count()).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestNewSessionFor(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	namedSession, err := ogm.NewSessionFor("neo4j", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	simpleNode := &SimpleNode{Prop1: "named"}
+	g.Expect(namedSession.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(namedSession.Clear()).NotTo(HaveOccurred())
+	var loaded *SimpleNode
+	g.Expect(namedSession.Load(&loaded, *simpleNode.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loaded.Prop1).To(Equal("named"))
+
+	tx, err := namedSession.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	loaded.Prop1 = "updated"
+	g.Expect(namedSession.Save(&loaded, nil)).NotTo(HaveOccurred())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+
+	var loadedByDefault *SimpleNode
+	g.Expect(session.Load(&loadedByDefault, *simpleNode.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loadedByDefault.Prop1).To(Equal("updated"), "neo4j is the default database")
+
+	var simpleNodeRef *SimpleNode
+	unknownSession, err := ogm.NewSessionFor("unknown", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = unknownSession.CountEntitiesOfType(&simpleNodeRef)
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestBookmarks(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	simpleNode := &SimpleNode{Prop1: "bookmarked"}
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	bookmark := session.LastBookmark()
+	g.Expect(bookmark).NotTo(BeEmpty())
+
+	readSession, err := ogm.NewSession(false, bookmark)
+	g.Expect(err).NotTo(HaveOccurred())
+	var loaded *SimpleNode
+	g.Expect(readSession.Load(&loaded, *simpleNode.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loaded.Prop1).To(Equal("bookmarked"))
+
+	tx, err := session.BeginTransaction()
+	g.Expect(err).NotTo(HaveOccurred())
+	simpleNode.Prop1 = "updated"
+	g.Expect(session.Save(&simpleNode, nil)).NotTo(HaveOccurred())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(session.LastBookmark()).NotTo(Equal(bookmark), "Committed transactions update the bookmark")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestInMemoryBackend(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	backend := inmemory.NewBackend()
+	inMemorySession, err := gogm.New(&gogm.Config{Backend: backend}).NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{Title: "The Matrix", Released: 1999}
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	neo := &Character{Movie: theMatrix, Actor: keanu, Roles: []string{"Neo"}, Name: "Neo"}
+	theMatrix.AddCharacter(neo)
+	g.Expect(inMemorySession.Save(&theMatrix, nil)).NotTo(HaveOccurred())
+	g.Expect(theMatrix.ID).NotTo(BeNil())
+	g.Expect(neo.ID).NotTo(BeNil())
+	g.Expect(backend.NodeCount()).To(Equal(2))
+	g.Expect(backend.RelationshipCount()).To(Equal(1))
+
+	g.Expect(inMemorySession.Clear()).NotTo(HaveOccurred())
+	var loadedTheMatrix *Movie
+	g.Expect(inMemorySession.Load(&loadedTheMatrix, *theMatrix.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loadedTheMatrix.Title).To(Equal("The Matrix"))
+	g.Expect(len(loadedTheMatrix.Characters)).To(Equal(1))
+	g.Expect(loadedTheMatrix.Characters[0].Roles).To(Equal([]string{"Neo"}))
+	g.Expect(loadedTheMatrix.Characters[0].Actor.Name).To(Equal("Keanu Reeves"))
+
+	count, err := inMemorySession.Count("MATCH (m:FILM) WHERE m.released < $year RETURN count(m)", map[string]interface{}{"year": 2000})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(inMemorySession.Delete(&loadedTheMatrix)).NotTo(HaveOccurred())
+	g.Expect(backend.NodeCount()).To(Equal(1))
+	g.Expect(backend.RelationshipCount()).To(Equal(0))
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fixtureFile, err := ioutil.TempFile("", "fixture*.json")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fixtureFile.Close()).NotTo(HaveOccurred())
+	defer os.Remove(fixtureFile.Name())
+
+	saveAndLoad := func(session gogm.Session) *Movie {
+		theMatrix := &Movie{Title: "The Matrix", Released: 1999}
+		g.Expect(session.Save(&theMatrix, nil)).NotTo(HaveOccurred())
+		g.Expect(session.Clear()).NotTo(HaveOccurred())
+		var loadedTheMatrix *Movie
+		g.Expect(session.Load(&loadedTheMatrix, *theMatrix.ID, nil)).NotTo(HaveOccurred())
+		return loadedTheMatrix
+	}
+
+	recorder := fixture.NewRecorder(inmemory.NewBackend(), fixtureFile.Name())
+	recordingSession, err := gogm.New(&gogm.Config{Backend: recorder}).NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	recordedTheMatrix := saveAndLoad(recordingSession)
+	g.Expect(recorder.Close()).NotTo(HaveOccurred())
+
+	replayer, err := fixture.NewReplayer(fixtureFile.Name())
+	g.Expect(err).NotTo(HaveOccurred())
+	replayingSession, err := gogm.New(&gogm.Config{Backend: replayer}).NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	replayedTheMatrix := saveAndLoad(replayingSession)
+	g.Expect(*replayedTheMatrix.ID).To(Equal(*recordedTheMatrix.ID))
+	g.Expect(replayedTheMatrix.Title).To(Equal("The Matrix"))
+	g.Expect(replayedTheMatrix.Released).To(Equal(int64(1999)))
+	g.Expect(replayer.Remaining()).To(Equal(0))
+
+	_, err = replayingSession.Count("MATCH (m:FILM) RETURN count(m)", nil)
+	g.Expect(errors.Is(err, fixture.ErrUnexpectedStatement)).To(BeTrue())
+}
+
+func TestDeterministicCypher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	record := func() []byte {
+		fixtureFile, err := ioutil.TempFile("", "fixture*.json")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(fixtureFile.Close()).NotTo(HaveOccurred())
+		defer os.Remove(fixtureFile.Name())
+
+		recorder := fixture.NewRecorder(inmemory.NewBackend(), fixtureFile.Name())
+		recordingSession, err := gogm.New(&gogm.Config{Backend: recorder}).NewSession(true)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		theMatrix := &Movie{Title: "The Matrix", Released: 1999}
+		for _, name := range []string{"Keanu Reeves", "Carrie-Anne Moss", "Laurence Fishburne"} {
+			actor := &Actor{}
+			actor.Name = name
+			theMatrix.AddCharacter(&Character{Movie: theMatrix, Actor: actor, Name: name})
+		}
+		g.Expect(recordingSession.Save(&theMatrix, nil)).NotTo(HaveOccurred())
+		theMatrix.Characters = theMatrix.Characters[1:]
+		g.Expect(recordingSession.Save(&theMatrix, nil)).NotTo(HaveOccurred())
+		g.Expect(recorder.Close()).NotTo(HaveOccurred())
+
+		interactions, err := ioutil.ReadFile(fixtureFile.Name())
+		g.Expect(err).NotTo(HaveOccurred())
+		return interactions
+	}
+
+	interactions := record()
+	for i := 0; i < 10; i++ {
+		g.Expect(string(record())).To(Equal(string(interactions)))
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	var movieRef *Movie
+	count := func() int64 {
+		count, err := session.CountEntitiesOfType(&movieRef)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	theMatrix := &Movie{Title: "The Matrix", Released: 1999}
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	neo := &Character{Movie: theMatrix, Actor: keanu, Name: "Neo"}
+	theMatrix.AddCharacter(neo)
+
+	saveOptions := gogm.NewSaveOptions()
+	saveOptions.Depth = -1
+	saveOptions.DryRun = true
+	saveOptions.Result = &gogm.SaveResult{}
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(theMatrix.ID).To(BeNil(), "Dry runs leave IDs untouched")
+	g.Expect(keanu.ID).To(BeNil())
+	g.Expect(neo.ID).To(BeNil())
+	g.Expect(count()).To(Equal(int64(0)), "Dry runs don't save")
+	g.Expect(len(saveOptions.Result.Statements)).To(Equal(1))
+	g.Expect(saveOptions.Result.Statements[0].Cypher).To(ContainSubstring("CREATE"))
+	g.Expect(saveOptions.Result.Created).To(ConsistOf(theMatrix, keanu, neo))
+	g.Expect(saveOptions.Result.Updated).To(BeEmpty())
+
+	saveOptions.BatchSize = 10
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(theMatrix.ID).To(BeNil())
+	g.Expect(len(saveOptions.Result.Statements)).To(Equal(3), "Batches create nodes by label, then relationships by type")
+	g.Expect(saveOptions.Result.Statements[0].Cypher).To(HavePrefix("UNWIND"))
+	g.Expect(saveOptions.Result.Created).To(ConsistOf(theMatrix, keanu, neo))
+
+	callbackNode := &CallbackNode{Name: " untrimmed "}
+	vetoingEventListener := &TestVetoingEventListener{Err: errors.New("vetoed")}
+	g.Expect(session.RegisterVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+	saveOptions = gogm.NewSaveOptions()
+	saveOptions.DryRun = true
+	saveOptions.Result = &gogm.SaveResult{}
+	g.Expect(session.Save(&callbackNode, saveOptions)).NotTo(HaveOccurred(), "Listeners aren't notified of dry runs")
+	g.Expect(session.DisposeVetoingEventListener(vetoingEventListener)).NotTo(HaveOccurred())
+	g.Expect(callbackNode.Callbacks).To(BeEmpty(), "Callbacks aren't called on dry runs")
+	g.Expect(callbackNode.Name).To(Equal(" untrimmed "))
+	g.Expect(saveOptions.Result.Statements[0].Parameters).To(ContainElement(HaveKeyWithValue("name", " untrimmed ")))
+
+	g.Expect(session.Save(&theMatrix, nil)).NotTo(HaveOccurred())
+	theMatrix.Title = "The Matrix Reloaded"
+	theMatrix.Characters = nil
+	saveOptions = gogm.NewSaveOptions()
+	saveOptions.Depth = -1
+	saveOptions.DryRun = true
+	saveOptions.Result = &gogm.SaveResult{}
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(len(saveOptions.Result.Statements)).To(Equal(1))
+	g.Expect(saveOptions.Result.Statements[0].Cypher).To(ContainSubstring("DELETE"))
+	g.Expect(saveOptions.Result.Created).To(BeEmpty())
+	g.Expect(saveOptions.Result.Updated).To(ConsistOf(theMatrix))
+	g.Expect(saveOptions.Result.Unchanged).To(ConsistOf(keanu))
+	g.Expect(saveOptions.Result.Deleted).To(ConsistOf(neo))
+
+	g.Expect(session.Save(&theMatrix, nil)).NotTo(HaveOccurred(), "Dry runs leave the session untouched")
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	var loadedTheMatrix *Movie
+	g.Expect(session.Load(&loadedTheMatrix, *theMatrix.ID, nil)).NotTo(HaveOccurred())
+	g.Expect(loadedTheMatrix.Title).To(Equal("The Matrix Reloaded"))
+	g.Expect(loadedTheMatrix.Characters).To(BeEmpty())
+
+	deleteOptions := gogm.NewDeleteOptions()
+	deleteOptions.DryRun = true
+	deleteOptions.Result = &gogm.DeleteResult{}
+	g.Expect(session.DeleteWithOptions(&loadedTheMatrix, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(len(deleteOptions.Result.Statements)).To(Equal(1))
+	g.Expect(deleteOptions.Result.Statements[0].Cypher).To(ContainSubstring("DETACH DELETE"))
+	g.Expect(count()).To(Equal(int64(1)), "Dry runs don't delete")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
+
+func TestSaveResult(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{Title: "The Matrix", Released: 1999}
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	neo := &Character{Movie: theMatrix, Actor: keanu, Name: "Neo"}
+	theMatrix.AddCharacter(neo)
+
+	saveOptions := gogm.NewSaveOptions()
+	saveOptions.Depth = -1
+	saveOptions.Result = &gogm.SaveResult{}
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(saveOptions.Result.Created).To(ConsistOf(theMatrix, keanu, neo))
+	g.Expect(saveOptions.Result.Updated).To(BeEmpty())
+	g.Expect(saveOptions.Result.Unchanged).To(BeEmpty())
+	g.Expect(saveOptions.Result.SavedDepth).To(Equal(1))
+	g.Expect(saveOptions.Result.Counters.NodesCreated).To(Equal(2))
+	g.Expect(saveOptions.Result.Counters.RelationshipsCreated).To(Equal(1))
+	g.Expect(saveOptions.Result.Counters.LabelsAdded).To(Equal(5))
+
+	theMatrix.Tagline = "Welcome to the Real World"
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(saveOptions.Result.Created).To(BeEmpty())
+	g.Expect(saveOptions.Result.Updated).To(ConsistOf(theMatrix))
+	g.Expect(saveOptions.Result.Unchanged).To(ConsistOf(keanu, neo))
+	g.Expect(saveOptions.Result.Counters.NodesCreated).To(Equal(0))
+	g.Expect(saveOptions.Result.Counters.PropertiesSet).To(Equal(1))
+
+	theMatrix.Characters = nil
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(saveOptions.Result.Deleted).To(ConsistOf(neo))
+	g.Expect(saveOptions.Result.Counters.RelationshipsDeleted).To(Equal(1))
+
+	deleteOptions := gogm.NewDeleteOptions()
+	deleteOptions.Result = &gogm.DeleteResult{}
+	g.Expect(session.DeleteWithOptions(&theMatrix, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(deleteOptions.Result.Counters.NodesDeleted).To(Equal(1))
+
+	options := gogm.NewSaveOptions()
+	simpleNode := &SimpleNode{}
+	g.Expect(session.Save(&simpleNode, options)).NotTo(HaveOccurred())
+	g.Expect(options).To(Equal(gogm.NewSaveOptions()), "Options without a Result aren't written to")
+
+	g.Expect(session.PurgeDatabase()).NotTo(HaveOccurred())
+}
@@ -70,6 +70,17 @@ func unloadGraphID(g graph, id *int64) {
 	}
 }
 
+//clearInternalIDs clears the IDs of the objects of the new graphs of s. New graphs have internal IDs, given to save them
+func clearInternalIDs(s store) {
+	for _, g := range s.all() {
+		if g.getID() < initialGraphID {
+			if IDAddr := getIDAddr(g); IDAddr != nil {
+				*IDAddr = nil
+			}
+		}
+	}
+}
+
 func getIDAddr(g graph) **int64 {
 	if g.getValue() != nil && g.getValue().IsValid() {
 		internalIDField := g.getValue().Elem().FieldByName(strings.ToUpper(idPropertyName))
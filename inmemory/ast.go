@@ -0,0 +1,261 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+//statement is a parsed Cypher statement. It's either a schema command, or clauses run in order on rows
+type statement struct {
+	clauses []clause
+	schema  schemaCommand
+}
+
+//clause transforms the rows produced by the previous clause of a statement
+type clause interface {
+	execute(e *execution, rows []row) ([]row, error)
+}
+
+//expression is evaluated in the scope of a row
+type expression interface {
+	evaluate(s *scope) (interface{}, error)
+}
+
+type direction int
+
+const (
+	either direction = iota
+	outgoing
+	incoming
+)
+
+//unbounded is the maximum number of hops of a variable length relationship pattern without upper bound
+const unbounded = -1
+
+type nodePattern struct {
+	variable   string
+	labels     []string
+	properties *mapLiteral
+}
+
+type relationshipPattern struct {
+	variable       string
+	types          []string
+	properties     *mapLiteral
+	direction      direction
+	variableLength bool
+	minHops        int
+	maxHops        int
+}
+
+//pattern is a path pattern. relationships[i] connects nodes[i] and nodes[i+1]
+type pattern struct {
+	pathVariable  string
+	nodes         []*nodePattern
+	relationships []*relationshipPattern
+}
+
+type matchClause struct {
+	optional bool
+	patterns []*pattern
+	where    expression
+}
+
+type createClause struct {
+	patterns []*pattern
+}
+
+type mergeClause struct {
+	pattern  *pattern
+	onCreate []*setItem
+	onMatch  []*setItem
+}
+
+type setItemKind int
+
+const (
+	setProperty setItemKind = iota
+	mergeProperties
+	replaceProperties
+	setLabels
+	removeProperty
+	removeLabels
+)
+
+type setItem struct {
+	kind     setItemKind
+	variable string
+	property string
+	labels   []string
+	value    expression
+}
+
+type setClause struct {
+	items []*setItem
+}
+
+type deleteClause struct {
+	detach      bool
+	expressions []expression
+}
+
+type unwindClause struct {
+	list     expression
+	variable string
+}
+
+type projectionItem struct {
+	expression expression
+	name       string
+}
+
+type sortItem struct {
+	expression expression
+	descending bool
+}
+
+//projectionClause is a WITH or a RETURN clause
+type projectionClause struct {
+	isReturn bool
+	distinct bool
+	star     bool
+	items    []*projectionItem
+	orderBy  []*sortItem
+	skip     expression
+	limit    expression
+	where    expression
+}
+
+type schemaCommandKind int
+
+const (
+	createIndex schemaCommandKind = iota
+	createUniqueConstraint
+	dropIndex
+	dropUniqueConstraint
+)
+
+//schemaCommand is an index or a constraint statement
+type schemaCommand struct {
+	kind       schemaCommandKind
+	label      string
+	properties []string
+}
+
+type literal struct {
+	value interface{}
+}
+
+type parameter struct {
+	name string
+}
+
+type variable struct {
+	name string
+}
+
+type propertyAccess struct {
+	subject  expression
+	property string
+}
+
+type indexAccess struct {
+	subject expression
+	index   expression
+}
+
+type sliceAccess struct {
+	subject expression
+	from    expression
+	to      expression
+}
+
+type labelPredicate struct {
+	subject expression
+	labels  []string
+}
+
+type notExpression struct {
+	operand expression
+}
+
+type negation struct {
+	operand expression
+}
+
+type binaryExpression struct {
+	operator string
+	left     expression
+	right    expression
+}
+
+type nullPredicate struct {
+	operand expression
+	not     bool
+}
+
+type functionCall struct {
+	name      string
+	distinct  bool
+	star      bool
+	arguments []expression
+}
+
+type listLiteral struct {
+	items []expression
+}
+
+type mapLiteral struct {
+	keys   []string
+	values []expression
+}
+
+type mapProjection struct {
+	variable string
+	keys     []string
+	values   []expression
+}
+
+type listComprehension struct {
+	variable   string
+	list       expression
+	where      expression
+	projection expression
+}
+
+type quantifier struct {
+	kind     string
+	variable string
+	list     expression
+	where    expression
+}
+
+type patternComprehension struct {
+	pattern    *pattern
+	where      expression
+	projection expression
+}
+
+type caseExpression struct {
+	subject     expression
+	conditions  []expression
+	results     []expression
+	defaultCase expression
+}
@@ -0,0 +1,272 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//Package inmemory is a graph database held in memory, for running the OGM without a Neo4j database.
+//It runs the subset of Cypher the OGM generates to save, load, count and delete entities
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//defaultDatabase is the name of the database of the backend
+const defaultDatabase = "neo4j"
+
+//Backend is an in memory graph database. Its sessions run statements in transactions isolated from each other,
+//committed to the graph of the backend
+type Backend struct {
+	mu                 sync.Mutex
+	graph              *graph
+	nextNodeID         int64
+	nextRelationshipID int64
+	commits            int64
+
+	//indexes are the keys of the indexes. constraints are the unique properties of labels
+	indexes     map[string]bool
+	constraints map[string]map[string]bool
+
+	//statements are the parsed statements, by their text
+	statements map[string]*statement
+}
+
+//NewBackend creates an empty in memory graph database
+func NewBackend() *Backend {
+	return &Backend{
+		graph:       newGraph(),
+		indexes:     map[string]bool{},
+		constraints: map[string]map[string]bool{},
+		statements:  map[string]*statement{}}
+}
+
+//NewSession opens a session of the backend. Transactions are committed to the backend as soon as they are committed,
+//hence bookmarks are always satisfied. The access mode isn't enforced. The backend holds a single database, named
+//like the default database of Neo4j
+func (b *Backend) NewSession(config neo4j.SessionConfig) (neo4j.Session, error) {
+	if config.DatabaseName != "" && config.DatabaseName != defaultDatabase {
+		return nil, errors.New("Database does not exist. Database name: '" + config.DatabaseName + "'")
+	}
+	s := &session{backend: b, accessMode: config.AccessMode}
+	if len(config.Bookmarks) > 0 {
+		s.lastBookmark = config.Bookmarks[len(config.Bookmarks)-1]
+	}
+	return s, nil
+}
+
+//NodeCount returns the number of nodes committed to the backend
+func (b *Backend) NodeCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.graph.nodes)
+}
+
+//RelationshipCount returns the number of relationships committed to the backend
+func (b *Backend) RelationshipCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.graph.relationships)
+}
+
+func (b *Backend) parse(cypher string) (*statement, error) {
+	b.mu.Lock()
+	s := b.statements[cypher]
+	b.mu.Unlock()
+	if s != nil {
+		return s, nil
+	}
+
+	s, err := parse(cypher)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.statements[cypher] = s
+	b.mu.Unlock()
+	return s, nil
+}
+
+//snapshot returns a copy of the committed graph, for a transaction to change
+func (b *Backend) snapshot() *graph {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.graph.clone()
+}
+
+func (b *Backend) newNodeID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextNodeID
+	b.nextNodeID++
+	return id
+}
+
+func (b *Backend) newRelationshipID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextRelationshipID
+	b.nextRelationshipID++
+	return id
+}
+
+//commit applies the nodes and relationships changed by t to the graph of the backend, and returns the bookmark of t
+func (b *Backend) commit(t *transaction) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	//Changes of concurrent transactions conflict when relationships are left without their nodes
+	for id := range t.dirtyNodes {
+		if t.graph.nodes[id] != nil {
+			continue
+		}
+		for relationshipID := range b.graph.nodeRelationships[id] {
+			if !t.dirtyRelationships[relationshipID] {
+				return "", errors.New("Transaction conflicts with a concurrent transaction. Node " + strconv.FormatInt(id, 10) + " has new relationships")
+			}
+		}
+	}
+	for id := range t.dirtyRelationships {
+		r := t.graph.relationships[id]
+		if r == nil {
+			continue
+		}
+		for _, nodeID := range [2]int64{r.start, r.end} {
+			if b.graph.nodes[nodeID] == nil && !t.dirtyNodes[nodeID] {
+				return "", errors.New("Transaction conflicts with a concurrent transaction. Node " + strconv.FormatInt(nodeID, 10) + " was deleted")
+			}
+		}
+	}
+
+	for id := range t.dirtyRelationships {
+		if t.graph.relationships[id] == nil {
+			b.graph.removeRelationship(id)
+		}
+	}
+	for id := range t.dirtyNodes {
+		if n := t.graph.nodes[id]; n != nil {
+			b.graph.putNode(n.clone())
+		} else {
+			b.graph.removeNode(id)
+		}
+	}
+	for id := range t.dirtyRelationships {
+		if r := t.graph.relationships[id]; r != nil {
+			b.graph.putRelationship(r.clone())
+		}
+	}
+
+	if len(t.dirtyNodes) > 0 || len(t.dirtyRelationships) > 0 {
+		b.commits++
+	}
+	return "inmemory:" + strconv.FormatInt(b.commits, 10), nil
+}
+
+//runSchemaCommand creates or drops an index or a unique constraint. Schema changes aren't transactional
+func (b *Backend) runSchemaCommand(command schemaCommand) (*counters, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var (
+		c           = &counters{}
+		description = ":" + command.label + "(" + strings.Join(command.properties, ",") + ")"
+	)
+	switch command.kind {
+	case createIndex:
+		if !b.indexes[description] {
+			b.indexes[description] = true
+			c.indexesAdded++
+		}
+	case dropIndex:
+		if !b.indexes[description] {
+			return nil, errors.New("Unable to drop index on " + description + ": No such INDEX ON " + description)
+		}
+		delete(b.indexes, description)
+		c.indexesRemoved++
+	case createUniqueConstraint:
+		property := command.properties[0]
+		if b.constraints[command.label][property] {
+			break
+		}
+		if err := checkUniqueness(b.graph, command.label, property); err != nil {
+			return nil, err
+		}
+		if b.constraints[command.label] == nil {
+			b.constraints[command.label] = map[string]bool{}
+		}
+		b.constraints[command.label][property] = true
+		c.constraintsAdded++
+	case dropUniqueConstraint:
+		property := command.properties[0]
+		if !b.constraints[command.label][property] {
+			return nil, errors.New("No such constraint " + description)
+		}
+		delete(b.constraints[command.label], property)
+		c.constraintsRemoved++
+	}
+	return c, nil
+}
+
+//checkConstraints returns an error when nodes of g violate the unique constraints of the backend
+func (b *Backend) checkConstraints(g *graph) error {
+	b.mu.Lock()
+	var labels []string
+	constraints := map[string][]string{}
+	for label, properties := range b.constraints {
+		for property := range properties {
+			constraints[label] = append(constraints[label], property)
+		}
+		sort.Strings(constraints[label])
+		labels = append(labels, label)
+	}
+	b.mu.Unlock()
+
+	sort.Strings(labels)
+	for _, label := range labels {
+		for _, property := range constraints[label] {
+			if err := checkUniqueness(g, label, property); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkUniqueness(g *graph, label string, property string) error {
+	owners := map[string]int64{}
+	for _, n := range g.nodesWithLabel(label) {
+		value := n.props[property]
+		if value == nil {
+			continue
+		}
+		key := valueKey(value)
+		if _, exists := owners[key]; exists {
+			return fmt.Errorf("Node(%d) already exists with label `%s` and property `%s` = %v", owners[key], label, property, value)
+		}
+		owners[key] = n.id
+	}
+	return nil
+}
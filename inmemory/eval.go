@@ -0,0 +1,602 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//scope holds the variables an expression is evaluated with. Comprehensions and quantifiers evaluate
+//their expressions in a nested scope binding their variable
+type scope struct {
+	execution *execution
+	parent    *scope
+	variables map[string]interface{}
+}
+
+func (s *scope) lookup(name string) (interface{}, bool) {
+	for current := s; current != nil; current = current.parent {
+		if value, bound := current.variables[name]; bound {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (s *scope) with(variables map[string]interface{}) *scope {
+	return &scope{execution: s.execution, parent: s, variables: variables}
+}
+
+func (l *literal) evaluate(s *scope) (interface{}, error) {
+	return l.value, nil
+}
+
+func (p *parameter) evaluate(s *scope) (interface{}, error) {
+	value, exists := s.execution.parameters[p.name]
+	if !exists {
+		return nil, errors.New("Expected parameter(s): " + p.name)
+	}
+	return value, nil
+}
+
+func (v *variable) evaluate(s *scope) (interface{}, error) {
+	value, bound := s.lookup(v.name)
+	if !bound {
+		return nil, errors.New("Variable `" + v.name + "` not defined")
+	}
+	return value, nil
+}
+
+func (p *propertyAccess) evaluate(s *scope) (interface{}, error) {
+	subject, err := p.subject.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+	switch v := subject.(type) {
+	case nil:
+		return nil, nil
+	case *node:
+		return v.props[p.property], nil
+	case *relationship:
+		return v.props[p.property], nil
+	case map[string]interface{}:
+		return v[p.property], nil
+	}
+	return nil, errors.New("Type mismatch: expected a map, a node or a relationship but was " + typeName(subject))
+}
+
+func (i *indexAccess) evaluate(s *scope) (interface{}, error) {
+	subject, err := i.subject.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.index.evaluate(s)
+	if err != nil || subject == nil || index == nil {
+		return nil, err
+	}
+	switch v := subject.(type) {
+	case []interface{}:
+		position, isInteger := index.(int64)
+		if !isInteger {
+			return nil, errors.New("Expected an Integer to access a list, but was " + typeName(index))
+		}
+		if position < 0 {
+			position += int64(len(v))
+		}
+		if position < 0 || position >= int64(len(v)) {
+			return nil, nil
+		}
+		return v[position], nil
+	case map[string]interface{}, *node, *relationship:
+		key, isString := index.(string)
+		if !isString {
+			return nil, errors.New("Expected a String to access a property, but was " + typeName(index))
+		}
+		return (&propertyAccess{&literal{v}, key}).evaluate(s)
+	}
+	return nil, errors.New("Type mismatch: expected a list or a map but was " + typeName(subject))
+}
+
+func (l *sliceAccess) evaluate(s *scope) (interface{}, error) {
+	subject, err := l.subject.evaluate(s)
+	if err != nil || subject == nil {
+		return nil, err
+	}
+	list, isList := subject.([]interface{})
+	if !isList {
+		return nil, errors.New("Type mismatch: expected a list but was " + typeName(subject))
+	}
+	bounds := [2]int64{0, int64(len(list))}
+	for index, bound := range [2]expression{l.from, l.to} {
+		if bound == nil {
+			continue
+		}
+		value, err := bound.evaluate(s)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		position, isInteger := value.(int64)
+		if !isInteger {
+			return nil, errors.New("Expected an Integer to slice a list, but was " + typeName(value))
+		}
+		if position < 0 {
+			position += int64(len(list))
+		}
+		bounds[index] = int64(math.Max(0, math.Min(float64(position), float64(len(list)))))
+	}
+	if bounds[0] >= bounds[1] {
+		return []interface{}{}, nil
+	}
+	return append([]interface{}{}, list[bounds[0]:bounds[1]]...), nil
+}
+
+func (l *labelPredicate) evaluate(s *scope) (interface{}, error) {
+	subject, err := l.subject.evaluate(s)
+	if err != nil || subject == nil {
+		return nil, err
+	}
+	n, isNode := subject.(*node)
+	if !isNode {
+		return nil, errors.New("Type mismatch: expected a node but was " + typeName(subject))
+	}
+	for _, label := range l.labels {
+		if !n.hasLabel(label) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (n *notExpression) evaluate(s *scope) (interface{}, error) {
+	operand, err := n.operand.evaluate(s)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	b, isBool := operand.(bool)
+	if !isBool {
+		return nil, errors.New("Type mismatch: expected a Boolean but was " + typeName(operand))
+	}
+	return !b, nil
+}
+
+func (n *negation) evaluate(s *scope) (interface{}, error) {
+	operand, err := n.operand.evaluate(s)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	switch v := operand.(type) {
+	case int64:
+		return -v, nil
+	case float64:
+		return -v, nil
+	}
+	return nil, errors.New("Type mismatch: expected a number but was " + typeName(operand))
+}
+
+func (n *nullPredicate) evaluate(s *scope) (interface{}, error) {
+	operand, err := n.operand.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+	return (operand == nil) != n.not, nil
+}
+
+func (b *binaryExpression) evaluate(s *scope) (interface{}, error) {
+	switch b.operator {
+	case "AND", "OR", "XOR":
+		return b.evaluateBoolean(s)
+	}
+
+	left, err := b.left.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+	right, err := b.right.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.operator {
+	case "=":
+		return equals(left, right), nil
+	case "<>":
+		equal := equals(left, right)
+		if equal == nil {
+			return nil, nil
+		}
+		return !equal.(bool), nil
+	case "<", "<=", ">", ">=":
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		comparison, comparable := compare(left, right)
+		if !comparable {
+			return nil, nil
+		}
+		switch b.operator {
+		case "<":
+			return comparison < 0, nil
+		case "<=":
+			return comparison <= 0, nil
+		case ">":
+			return comparison > 0, nil
+		}
+		return comparison >= 0, nil
+	case "IN":
+		return in(left, right)
+	case "=~", "STARTS WITH", "ENDS WITH", "CONTAINS":
+		return evaluateStringOperator(b.operator, left, right)
+	}
+	return evaluateArithmetic(b.operator, left, right)
+}
+
+//evaluateBoolean evaluates AND, OR and XOR with the three valued logic of Cypher
+func (b *binaryExpression) evaluateBoolean(s *scope) (interface{}, error) {
+	var operands [2]interface{}
+	for index, operand := range [2]expression{b.left, b.right} {
+		value, err := operand.evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, isBool := value.(bool); value != nil && !isBool {
+			return nil, errors.New("Type mismatch: expected a Boolean but was " + typeName(value))
+		}
+		operands[index] = value
+	}
+	left, right := operands[0], operands[1]
+	switch b.operator {
+	case "AND":
+		if left == false || right == false {
+			return false, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return true, nil
+	case "OR":
+		if left == true || right == true {
+			return true, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return false, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return left != right, nil
+}
+
+//in tells whether list contains value. It's null when list doesn't contain value but contains nulls
+func in(value interface{}, list interface{}) (interface{}, error) {
+	if list == nil {
+		return nil, nil
+	}
+	items, isList := list.([]interface{})
+	if !isList {
+		return nil, errors.New("Type mismatch: expected a list but was " + typeName(list))
+	}
+	var result interface{} = false
+	for _, item := range items {
+		switch equals(value, item) {
+		case true:
+			return true, nil
+		case nil:
+			result = nil
+		}
+	}
+	return result, nil
+}
+
+func evaluateStringOperator(operator string, left interface{}, right interface{}) (interface{}, error) {
+	l, isLeftString := left.(string)
+	r, isRightString := right.(string)
+	if !isLeftString || !isRightString {
+		return nil, nil
+	}
+	switch operator {
+	case "STARTS WITH":
+		return strings.HasPrefix(l, r), nil
+	case "ENDS WITH":
+		return strings.HasSuffix(l, r), nil
+	case "CONTAINS":
+		return strings.Contains(l, r), nil
+	}
+	re, err := regexp.Compile(`^(?:` + r + `)$`)
+	if err != nil {
+		return nil, errors.New("Invalid regular expression '" + r + "': " + err.Error())
+	}
+	return re.MatchString(l), nil
+}
+
+func evaluateArithmetic(operator string, left interface{}, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	if operator == "+" {
+		leftList, isLeftList := left.([]interface{})
+		rightList, isRightList := right.([]interface{})
+		switch {
+		case isLeftList && isRightList:
+			return append(append([]interface{}{}, leftList...), rightList...), nil
+		case isLeftList:
+			return append(append([]interface{}{}, leftList...), right), nil
+		case isRightList:
+			return append([]interface{}{left}, rightList...), nil
+		}
+		leftString, isLeftString := left.(string)
+		rightString, isRightString := right.(string)
+		switch {
+		case isLeftString && isRightString:
+			return leftString + rightString, nil
+		case isLeftString:
+			return leftString + toString(right), nil
+		case isRightString:
+			return toString(left) + rightString, nil
+		}
+	}
+
+	leftInteger, isLeftInteger := left.(int64)
+	rightInteger, isRightInteger := right.(int64)
+	if isLeftInteger && isRightInteger {
+		switch operator {
+		case "+":
+			return leftInteger + rightInteger, nil
+		case "-":
+			return leftInteger - rightInteger, nil
+		case "*":
+			return leftInteger * rightInteger, nil
+		}
+		if rightInteger == 0 {
+			return nil, errors.New("/ by zero")
+		}
+		if operator == "/" {
+			return leftInteger / rightInteger, nil
+		}
+		return leftInteger % rightInteger, nil
+	}
+
+	leftFloat, isLeftNumber := asFloat(left)
+	rightFloat, isRightNumber := asFloat(right)
+	if !isLeftNumber || !isRightNumber {
+		return nil, errors.New("Type mismatch: can't apply " + operator + " to " + typeName(left) + " and " + typeName(right))
+	}
+	switch operator {
+	case "+":
+		return leftFloat + rightFloat, nil
+	case "-":
+		return leftFloat - rightFloat, nil
+	case "*":
+		return leftFloat * rightFloat, nil
+	case "/":
+		return leftFloat / rightFloat, nil
+	}
+	return math.Mod(leftFloat, rightFloat), nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return valueKey(value)
+}
+
+func (l *listLiteral) evaluate(s *scope) (interface{}, error) {
+	list := make([]interface{}, len(l.items))
+	for index, item := range l.items {
+		value, err := item.evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		list[index] = value
+	}
+	return list, nil
+}
+
+func (m *mapLiteral) evaluate(s *scope) (interface{}, error) {
+	result := make(map[string]interface{}, len(m.keys))
+	for index, key := range m.keys {
+		value, err := m.values[index].evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (m *mapProjection) evaluate(s *scope) (interface{}, error) {
+	subject, err := (&variable{m.variable}).evaluate(s)
+	if err != nil || subject == nil {
+		return nil, err
+	}
+	switch subject.(type) {
+	case *node, *relationship, map[string]interface{}:
+	default:
+		return nil, errors.New("Type mismatch: expected a map, a node or a relationship but was " + typeName(subject))
+	}
+	return (&mapLiteral{m.keys, m.values}).evaluate(s)
+}
+
+//iterate evaluates list, and calls f with the scope of each of its items bound to variable
+func iterate(s *scope, variable string, list expression, f func(itemScope *scope, item interface{}) error) error {
+	value, err := list.evaluate(s)
+	if err != nil || value == nil {
+		return err
+	}
+	items, isList := value.([]interface{})
+	if !isList {
+		return errors.New("Type mismatch: expected a list but was " + typeName(value))
+	}
+	for _, item := range items {
+		if err = f(s.with(map[string]interface{}{variable: item}), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *listComprehension) evaluate(s *scope) (interface{}, error) {
+	if listValue, err := c.list.evaluate(s); err != nil || listValue == nil {
+		return nil, err
+	}
+	result := []interface{}{}
+	err := iterate(s, c.variable, c.list, func(itemScope *scope, item interface{}) error {
+		if c.where != nil {
+			kept, err := c.where.evaluate(itemScope)
+			if err != nil || !isTrue(kept) {
+				return err
+			}
+		}
+		if c.projection != nil {
+			var err error
+			if item, err = c.projection.evaluate(itemScope); err != nil {
+				return err
+			}
+		}
+		result = append(result, item)
+		return nil
+	})
+	return result, err
+}
+
+func (q *quantifier) evaluate(s *scope) (interface{}, error) {
+	if listValue, err := q.list.evaluate(s); err != nil || listValue == nil {
+		return nil, err
+	}
+	var trues, nulls, total int
+	err := iterate(s, q.variable, q.list, func(itemScope *scope, item interface{}) error {
+		value, err := q.where.evaluate(itemScope)
+		if err != nil {
+			return err
+		}
+		total++
+		if value == nil {
+			nulls++
+		} else if isTrue(value) {
+			trues++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	falses := total - trues - nulls
+	switch q.kind {
+	case "all":
+		if falses > 0 {
+			return false, nil
+		}
+		if nulls > 0 {
+			return nil, nil
+		}
+		return true, nil
+	case "any":
+		if trues > 0 {
+			return true, nil
+		}
+		if nulls > 0 {
+			return nil, nil
+		}
+		return false, nil
+	case "none":
+		if trues > 0 {
+			return false, nil
+		}
+		if nulls > 0 {
+			return nil, nil
+		}
+		return true, nil
+	}
+	if trues > 1 {
+		return false, nil
+	}
+	if nulls > 0 {
+		return nil, nil
+	}
+	return trues == 1, nil
+}
+
+func (c *patternComprehension) evaluate(s *scope) (interface{}, error) {
+	var (
+		result = []interface{}{}
+		m      = newMatcher(s)
+	)
+	err := m.match([]*pattern{c.pattern}, func() error {
+		matchScope := s.with(copyProperties(m.bindings))
+		if c.where != nil {
+			kept, err := c.where.evaluate(matchScope)
+			if err != nil || !isTrue(kept) {
+				return err
+			}
+		}
+		value, err := c.projection.evaluate(matchScope)
+		if err != nil {
+			return err
+		}
+		result = append(result, value)
+		return nil
+	})
+	return result, err
+}
+
+func (c *caseExpression) evaluate(s *scope) (interface{}, error) {
+	var (
+		subject interface{}
+		err     error
+	)
+	if c.subject != nil {
+		if subject, err = c.subject.evaluate(s); err != nil {
+			return nil, err
+		}
+	}
+	for index, condition := range c.conditions {
+		value, err := condition.evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		if c.subject != nil {
+			value = equals(subject, value)
+		}
+		if isTrue(value) {
+			return c.results[index].evaluate(s)
+		}
+	}
+	if c.defaultCase != nil {
+		return c.defaultCase.evaluate(s)
+	}
+	return nil, nil
+}
@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	. "github.com/onsi/gomega"
+)
+
+//people are the nodes and relationships the evaluated statements start from
+const people = `CREATE (a:Person {name: 'a', age: 30})-[:KNOWS {since: 2000}]->(b:Person {name: 'b', age: 40})-[:KNOWS {since: 2010}]->(c:Person {name: 'c'})`
+
+//run runs cypher with params in a session of backend, and returns the values of the records of the result
+func run(backend *Backend, cypher string, params map[string]interface{}) ([][]interface{}, error) {
+	session, err := backend.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	records, err := neo4j.Collect(session.Run(cypher, params))
+	if err != nil {
+		return nil, err
+	}
+	var values [][]interface{}
+	for _, record := range records {
+		values = append(values, record.Values())
+	}
+	return values, nil
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []string
+		params     map[string]interface{}
+		query      string
+		expected   [][]interface{}
+	}{
+		{
+			name:     "MATCH filters with WHERE",
+			query:    `MATCH (n:Person) WHERE n.age > 35 RETURN n.name`,
+			expected: [][]interface{}{{"b"}},
+		},
+		{
+			name:     "MATCH matches relationship patterns",
+			query:    `MATCH (n:Person)-[r:KNOWS]->(m:Person {name: 'c'}) RETURN n.name, r.since`,
+			expected: [][]interface{}{{"b", int64(2010)}},
+		},
+		{
+			name:     "OPTIONAL MATCH returns null when nothing matches",
+			query:    `MATCH (n:Person {name: 'c'}) OPTIONAL MATCH (n)-[:KNOWS]->(m) RETURN n.name, m`,
+			expected: [][]interface{}{{"c", nil}},
+		},
+		{
+			name:     "Variable length paths are bounded",
+			query:    `MATCH (n:Person {name: 'a'})-[*1..2]->(m) RETURN m.name ORDER BY m.name`,
+			expected: [][]interface{}{{"b"}, {"c"}},
+		},
+		{
+			name:     "Variable length paths match an exact length",
+			query:    `MATCH (n:Person {name: 'a'})-[*2]-(m) RETURN m.name`,
+			expected: [][]interface{}{{"c"}},
+		},
+		{
+			name:     "Variable length paths include zero hops",
+			query:    `MATCH (n:Person {name: 'b'})-[*0..1]->(m) RETURN m.name ORDER BY m.name`,
+			expected: [][]interface{}{{"b"}, {"c"}},
+		},
+		{
+			name:     "count counts rows",
+			query:    `MATCH (n:Person) RETURN count(n), count(n.age), count(*)`,
+			expected: [][]interface{}{{int64(3), int64(2), int64(3)}},
+		},
+		{
+			name:     "count groups by the other projected values",
+			query:    `MATCH (n:Person) OPTIONAL MATCH (n)-[r:KNOWS]->() RETURN n.name, count(r) ORDER BY n.name`,
+			expected: [][]interface{}{{"a", int64(1)}, {"b", int64(1)}, {"c", int64(0)}},
+		},
+		{
+			name:     "UNWIND produces a row per item",
+			params:   map[string]interface{}{"names": []interface{}{"a", "c"}},
+			query:    `UNWIND $names AS name MATCH (n:Person {name: name}) RETURN n.name`,
+			expected: [][]interface{}{{"a"}, {"c"}},
+		},
+		{
+			name:       "UNWIND creates nodes with SET +=",
+			statements: []string{`UNWIND $rows AS row CREATE (n:Person) SET n += row.properties`},
+			params:     map[string]interface{}{"rows": []interface{}{map[string]interface{}{"properties": map[string]interface{}{"name": "d", "age": 20}}}},
+			query:      `MATCH (n:Person) WHERE n.age < 25 RETURN n.name, n.age`,
+			expected:   [][]interface{}{{"d", int64(20)}},
+		},
+		{
+			name:       "SET += keeps the other properties",
+			statements: []string{`MATCH (n:Person {name: 'a'}) SET n += $properties`},
+			params:     map[string]interface{}{"properties": map[string]interface{}{"age": 31}},
+			query:      `MATCH (n:Person {name: 'a'}) RETURN n.name, n.age`,
+			expected:   [][]interface{}{{"a", int64(31)}},
+		},
+		{
+			name:       "MERGE matches existing nodes",
+			statements: []string{`MERGE (n:Person {name: 'a'}) ON MATCH SET n.merged = true`},
+			query:      `MATCH (n:Person) WHERE n.merged RETURN n.name, count(*)`,
+			expected:   [][]interface{}{{"a", int64(1)}},
+		},
+		{
+			name:       "MERGE creates missing nodes",
+			statements: []string{`MERGE (n:Person {name: 'd'}) ON CREATE SET n.created = true`},
+			query:      `MATCH (n:Person) RETURN count(n), count(n.created)`,
+			expected:   [][]interface{}{{int64(4), int64(1)}},
+		},
+		{
+			name:       "MERGE creates missing relationships",
+			statements: []string{`MATCH (a:Person {name: 'a'}), (c:Person {name: 'c'}) MERGE (a)-[:KNOWS]->(c) MERGE (a)-[:KNOWS]->(c)`},
+			query:      `MATCH (:Person {name: 'a'})-[r:KNOWS]->() RETURN count(r)`,
+			expected:   [][]interface{}{{int64(2)}},
+		},
+		{
+			name:       "DETACH DELETE deletes the relationships of nodes",
+			statements: []string{`MATCH (n:Person {name: 'b'}) DETACH DELETE n`},
+			query:      `MATCH (n:Person) OPTIONAL MATCH (n)-[r]-() RETURN count(DISTINCT n), count(r)`,
+			expected:   [][]interface{}{{int64(2), int64(0)}},
+		},
+	}
+	for _, test := range tests {
+		g := NewGomegaWithT(t)
+		backend := NewBackend()
+		_, err := run(backend, people, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, statement := range test.statements {
+			_, err = run(backend, statement, test.params)
+			g.Expect(err).NotTo(HaveOccurred(), test.name)
+		}
+		values, err := run(backend, test.query, test.params)
+		g.Expect(err).NotTo(HaveOccurred(), test.name)
+		g.Expect(values).To(Equal(test.expected), test.name)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Nodes with relationships can't be deleted without DETACH", `MATCH (n:Person {name: 'b'}) DELETE n`},
+		{"Parameters must be set", `MATCH (n:Person) WHERE n.name = $name RETURN n`},
+		{"Variables must be defined", `MATCH (n:Person) RETURN m`},
+		{"Properties are read from maps and entities", `UNWIND [1, 2] AS i RETURN i.name`},
+	}
+	for _, test := range tests {
+		g := NewGomegaWithT(t)
+		backend := NewBackend()
+		_, err := run(backend, people, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = run(backend, test.query, nil)
+		g.Expect(err).To(HaveOccurred(), test.name)
+	}
+}
@@ -0,0 +1,523 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+//row binds the variables of a statement to values
+type row map[string]interface{}
+
+func (r row) with(variables map[string]interface{}) row {
+	extended := make(row, len(r)+len(variables))
+	for name, value := range r {
+		extended[name] = value
+	}
+	for name, value := range variables {
+		extended[name] = value
+	}
+	return extended
+}
+
+//execution runs the clauses of a statement in a transaction
+type execution struct {
+	transaction *transaction
+	graph       *graph
+	parameters  map[string]interface{}
+	counters    *counters
+	updating    bool
+
+	//deletedNodes are the nodes deleted by the statement. Relationships deleted with them still reference them
+	deletedNodes map[int64]*node
+
+	//columns and records are the result of the RETURN clause
+	columns []string
+	records [][]interface{}
+}
+
+func newExecution(t *transaction, parameters map[string]interface{}) *execution {
+	return &execution{
+		transaction:  t,
+		graph:        t.graph,
+		parameters:   parameters,
+		counters:     &counters{},
+		deletedNodes: map[int64]*node{}}
+}
+
+func (e *execution) run(s *statement) error {
+	var (
+		rows = []row{{}}
+		err  error
+	)
+	for _, c := range s.clauses {
+		switch c.(type) {
+		case *createClause, *mergeClause, *setClause, *deleteClause:
+			e.updating = true
+		}
+		if rows, err = c.execute(e, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *execution) scope(r row) *scope {
+	return &scope{execution: e, variables: r}
+}
+
+//node returns the node with id, including nodes deleted by the statement
+func (e *execution) node(id int64) *node {
+	if n := e.graph.nodes[id]; n != nil {
+		return n
+	}
+	return e.deletedNodes[id]
+}
+
+func (e *execution) createNode(labels []string, properties map[string]interface{}) (*node, error) {
+	n := &node{id: e.transaction.session.backend.newNodeID(), props: map[string]interface{}{}}
+	e.graph.putNode(n)
+	e.transaction.dirtyNodes[n.id] = true
+	e.counters.nodesCreated++
+	for _, label := range labels {
+		if !n.hasLabel(label) {
+			n.labels = append(n.labels, label)
+			e.counters.labelsAdded++
+		}
+	}
+	return n, e.setProperties(n, properties, false)
+}
+
+func (e *execution) createRelationship(start *node, end *node, relType string, properties map[string]interface{}) (*relationship, error) {
+	if e.graph.nodes[start.id] == nil || e.graph.nodes[end.id] == nil {
+		return nil, errors.New("Can't create a relationship with a node deleted in this transaction")
+	}
+	r := &relationship{id: e.transaction.session.backend.newRelationshipID(), start: start.id, end: end.id, relType: relType, props: map[string]interface{}{}}
+	e.graph.putRelationship(r)
+	e.transaction.dirtyRelationships[r.id] = true
+	e.counters.relationshipsCreated++
+	return r, e.setProperties(r, properties, false)
+}
+
+func (e *execution) deleteNode(n *node, detach bool) error {
+	if e.graph.nodes[n.id] == nil {
+		return nil
+	}
+	relationships := e.graph.relationshipsOf(n.id)
+	if len(relationships) > 0 && !detach {
+		return errors.New("Cannot delete node<" + strconv.FormatInt(n.id, 10) + ">, because it still has relationships. To delete this node, you must first delete its relationships")
+	}
+	for _, r := range relationships {
+		e.deleteRelationship(r)
+	}
+	e.graph.removeNode(n.id)
+	e.deletedNodes[n.id] = n
+	e.transaction.dirtyNodes[n.id] = true
+	e.counters.nodesDeleted++
+	return nil
+}
+
+func (e *execution) deleteRelationship(r *relationship) {
+	if e.graph.relationships[r.id] == nil {
+		return
+	}
+	e.graph.removeRelationship(r.id)
+	e.transaction.dirtyRelationships[r.id] = true
+	e.counters.relationshipsDeleted++
+}
+
+//entityProperties returns the properties of the node or the relationship entity, and marks it as changed
+func (e *execution) entityProperties(entity interface{}) (map[string]interface{}, error) {
+	switch v := entity.(type) {
+	case *node:
+		if e.graph.nodes[v.id] != v {
+			return nil, errors.New("Node with id " + strconv.FormatInt(v.id, 10) + " has been deleted in this transaction")
+		}
+		e.transaction.dirtyNodes[v.id] = true
+		return v.props, nil
+	case *relationship:
+		if e.graph.relationships[v.id] != v {
+			return nil, errors.New("Relationship with id " + strconv.FormatInt(v.id, 10) + " has been deleted in this transaction")
+		}
+		e.transaction.dirtyRelationships[v.id] = true
+		return v.props, nil
+	}
+	return nil, errors.New("Expected a node or a relationship, but was " + typeName(entity))
+}
+
+//setProperty sets the property key of entity to value. A null value removes the property
+func (e *execution) setProperty(entity interface{}, key string, value interface{}) error {
+	props, err := e.entityProperties(entity)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		if _, exists := props[key]; exists {
+			delete(props, key)
+			e.counters.propertiesSet++
+		}
+		return nil
+	}
+	if err = checkPropertyValue(key, value); err != nil {
+		return err
+	}
+	props[key] = value
+	e.counters.propertiesSet++
+	return nil
+}
+
+//setProperties sets properties on entity. Other properties of entity are removed when replace is true
+func (e *execution) setProperties(entity interface{}, properties map[string]interface{}, replace bool) error {
+	if replace {
+		props, err := e.entityProperties(entity)
+		if err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(props) {
+			if _, kept := properties[key]; !kept {
+				if err = e.setProperty(entity, key, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, key := range sortedKeys(properties) {
+		if err := e.setProperty(entity, key, properties[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//evaluateProperties evaluates the property map of a pattern
+func evaluateProperties(properties *mapLiteral, s *scope) (map[string]interface{}, error) {
+	if properties == nil {
+		return nil, nil
+	}
+	value, err := properties.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]interface{}), nil
+}
+
+func (c *matchClause) execute(e *execution, rows []row) ([]row, error) {
+	var matched []row
+	for _, r := range rows {
+		var (
+			found = false
+			m     = newMatcher(e.scope(r))
+		)
+		if err := m.match(c.patterns, func() error {
+			candidate := r.with(m.bindings)
+			if c.where != nil {
+				value, err := c.where.evaluate(e.scope(candidate))
+				if err != nil || !isTrue(value) {
+					return err
+				}
+			}
+			found = true
+			matched = append(matched, candidate)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if !found && c.optional {
+			nulls := map[string]interface{}{}
+			for _, p := range c.patterns {
+				for _, name := range p.variables() {
+					if _, bound := r[name]; !bound {
+						nulls[name] = nil
+					}
+				}
+			}
+			matched = append(matched, r.with(nulls))
+		}
+	}
+	return matched, nil
+}
+
+//variables returns the variables of p
+func (p *pattern) variables() []string {
+	var names []string
+	if p.pathVariable != "" {
+		names = append(names, p.pathVariable)
+	}
+	for _, n := range p.nodes {
+		if n.variable != "" {
+			names = append(names, n.variable)
+		}
+	}
+	for _, r := range p.relationships {
+		if r.variable != "" {
+			names = append(names, r.variable)
+		}
+	}
+	return names
+}
+
+func (c *createClause) execute(e *execution, rows []row) ([]row, error) {
+	created := make([]row, 0, len(rows))
+	for _, r := range rows {
+		bindings := map[string]interface{}{}
+		for _, p := range c.patterns {
+			if err := e.create(p, e.scope(r).with(bindings), bindings); err != nil {
+				return nil, err
+			}
+		}
+		created = append(created, r.with(bindings))
+	}
+	return created, nil
+}
+
+//create creates the nodes and relationships of p which aren't bound in s. The created entities are added to bindings
+func (e *execution) create(p *pattern, s *scope, bindings map[string]interface{}) error {
+	nodes := make([]*node, len(p.nodes))
+	for index, np := range p.nodes {
+		if np.variable != "" {
+			if value, bound := s.lookup(np.variable); bound {
+				n, isNode := value.(*node)
+				if !isNode {
+					return errors.New("Expected " + np.variable + " to be a node, but was " + typeName(value))
+				}
+				nodes[index] = n
+				continue
+			}
+		}
+		properties, err := evaluateProperties(np.properties, s)
+		if err != nil {
+			return err
+		}
+		if nodes[index], err = e.createNode(np.labels, properties); err != nil {
+			return err
+		}
+		if np.variable != "" {
+			bindings[np.variable] = nodes[index]
+		}
+	}
+
+	created := &path{nodes: nodes}
+	for index, rp := range p.relationships {
+		if len(rp.types) != 1 || rp.variableLength {
+			return errors.New("A single relationship type must be specified for CREATE")
+		}
+		if rp.variable != "" {
+			if _, bound := s.lookup(rp.variable); bound {
+				return errors.New("Can't create relationship " + rp.variable + ", the variable is already declared")
+			}
+		}
+		start, end := nodes[index], nodes[index+1]
+		if rp.direction == incoming {
+			start, end = end, start
+		}
+		properties, err := evaluateProperties(rp.properties, s)
+		if err != nil {
+			return err
+		}
+		var r *relationship
+		if r, err = e.createRelationship(start, end, rp.types[0], properties); err != nil {
+			return err
+		}
+		created.relationships = append(created.relationships, r)
+		if rp.variable != "" {
+			bindings[rp.variable] = r
+		}
+	}
+	if p.pathVariable != "" {
+		bindings[p.pathVariable] = created
+	}
+	return nil
+}
+
+func (c *mergeClause) execute(e *execution, rows []row) ([]row, error) {
+	var merged []row
+	for _, r := range rows {
+		var (
+			matches []row
+			m       = newMatcher(e.scope(r))
+		)
+		if err := m.match([]*pattern{c.pattern}, func() error {
+			matches = append(matches, r.with(m.bindings))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		items := c.onMatch
+		if len(matches) == 0 {
+			bindings := map[string]interface{}{}
+			if err := e.create(c.pattern, e.scope(r).with(bindings), bindings); err != nil {
+				return nil, err
+			}
+			matches = []row{r.with(bindings)}
+			items = c.onCreate
+		}
+		for _, match := range matches {
+			for _, item := range items {
+				if err := e.set(item, e.scope(match)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		merged = append(merged, matches...)
+	}
+	return merged, nil
+}
+
+func (c *setClause) execute(e *execution, rows []row) ([]row, error) {
+	for _, r := range rows {
+		for _, item := range c.items {
+			if err := e.set(item, e.scope(r)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rows, nil
+}
+
+//set applies a SET or a REMOVE item
+func (e *execution) set(item *setItem, s *scope) error {
+	target, _ := s.lookup(item.variable)
+	if target == nil {
+		return nil
+	}
+
+	var (
+		value interface{}
+		err   error
+	)
+	if item.value != nil {
+		if value, err = item.value.evaluate(s); err != nil {
+			return err
+		}
+	}
+
+	switch item.kind {
+	case setProperty:
+		return e.setProperty(target, item.property, value)
+	case removeProperty:
+		return e.setProperty(target, item.property, nil)
+	case mergeProperties, replaceProperties:
+		var properties map[string]interface{}
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			properties = v
+		case *node:
+			properties = copyProperties(v.props)
+		case *relationship:
+			properties = copyProperties(v.props)
+		default:
+			return errors.New("Expected a map to set the properties of " + item.variable + ", but was " + typeName(value))
+		}
+		return e.setProperties(target, properties, item.kind == replaceProperties)
+	case setLabels, removeLabels:
+		n, isNode := target.(*node)
+		if !isNode {
+			return errors.New("Expected " + item.variable + " to be a node, but was " + typeName(target))
+		}
+		if _, err = e.entityProperties(n); err != nil {
+			return err
+		}
+		for _, label := range item.labels {
+			switch {
+			case item.kind == setLabels && !n.hasLabel(label):
+				n.labels = append(n.labels, label)
+				e.counters.labelsAdded++
+			case item.kind == removeLabels && n.hasLabel(label):
+				for index, l := range n.labels {
+					if l == label {
+						n.labels = append(n.labels[:index:index], n.labels[index+1:]...)
+						break
+					}
+				}
+				e.counters.labelsRemoved++
+			}
+		}
+	}
+	return nil
+}
+
+func (c *deleteClause) execute(e *execution, rows []row) ([]row, error) {
+	for _, r := range rows {
+		var nodes []*node
+		for _, expression := range c.expressions {
+			value, err := expression.evaluate(e.scope(r))
+			if err != nil {
+				return nil, err
+			}
+			switch v := value.(type) {
+			case nil:
+			case *node:
+				nodes = append(nodes, v)
+			case *relationship:
+				e.deleteRelationship(v)
+			case *path:
+				for _, relationship := range v.relationships {
+					e.deleteRelationship(relationship)
+				}
+				nodes = append(nodes, v.nodes...)
+			default:
+				return nil, errors.New("Expected a node, a relationship or a path to delete, but was " + typeName(value))
+			}
+		}
+		//Nodes are deleted after the relationships deleted with them
+		for _, n := range nodes {
+			if err := e.deleteNode(n, c.detach); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (c *unwindClause) execute(e *execution, rows []row) ([]row, error) {
+	var unwound []row
+	for _, r := range rows {
+		value, err := c.list.evaluate(e.scope(r))
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range v {
+				unwound = append(unwound, r.with(map[string]interface{}{c.variable: item}))
+			}
+		default:
+			unwound = append(unwound, r.with(map[string]interface{}{c.variable: v}))
+		}
+	}
+	return unwound, nil
+}
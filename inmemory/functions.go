@@ -0,0 +1,377 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//aggregates are the aggregating functions. They are evaluated by the projection of WITH and RETURN clauses
+var aggregates = map[string]bool{"count": true, "collect": true, "sum": true, "min": true, "max": true, "avg": true}
+
+func (f *functionCall) evaluate(s *scope) (interface{}, error) {
+	if aggregates[f.name] {
+		return nil, errors.New("Aggregate function " + f.name + "() is only supported as a projected item of WITH or RETURN")
+	}
+
+	arguments := make([]interface{}, len(f.arguments))
+	for index, argument := range f.arguments {
+		value, err := argument.evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		arguments[index] = value
+	}
+
+	if f.name == "coalesce" {
+		for _, argument := range arguments {
+			if argument != nil {
+				return argument, nil
+			}
+		}
+		return nil, nil
+	}
+	if f.name == "range" {
+		return evaluateRange(arguments)
+	}
+
+	function, exists := functions[f.name]
+	if !exists && f.name != "startnode" && f.name != "endnode" {
+		return nil, errors.New("Unknown function '" + f.name + "'")
+	}
+	if len(arguments) != 1 {
+		return nil, errors.New("Function " + f.name + "() expects one argument")
+	}
+	if arguments[0] == nil {
+		return nil, nil
+	}
+	switch f.name {
+	case "startnode":
+		return relationshipNode(s, arguments[0], true)
+	case "endnode":
+		return relationshipNode(s, arguments[0], false)
+	}
+	return function(arguments[0])
+}
+
+//functions are the functions of one argument. They aren't called with null, their result is null
+var functions = map[string]func(argument interface{}) (interface{}, error){
+	"id": func(argument interface{}) (interface{}, error) {
+		switch v := argument.(type) {
+		case *node:
+			return v.id, nil
+		case *relationship:
+			return v.id, nil
+		}
+		return nil, typeMismatch("a node or a relationship", argument)
+	},
+	"type": func(argument interface{}) (interface{}, error) {
+		if r, isRelationship := argument.(*relationship); isRelationship {
+			return r.relType, nil
+		}
+		return nil, typeMismatch("a relationship", argument)
+	},
+	"nodes": func(argument interface{}) (interface{}, error) {
+		p, isPath := argument.(*path)
+		if !isPath {
+			return nil, typeMismatch("a path", argument)
+		}
+		nodes := make([]interface{}, len(p.nodes))
+		for index, n := range p.nodes {
+			nodes[index] = n
+		}
+		return nodes, nil
+	},
+	"relationships": func(argument interface{}) (interface{}, error) {
+		p, isPath := argument.(*path)
+		if !isPath {
+			return nil, typeMismatch("a path", argument)
+		}
+		relationships := make([]interface{}, len(p.relationships))
+		for index, r := range p.relationships {
+			relationships[index] = r
+		}
+		return relationships, nil
+	},
+	"length": func(argument interface{}) (interface{}, error) {
+		switch v := argument.(type) {
+		case *path:
+			return int64(len(v.relationships)), nil
+		case string:
+			return int64(len([]rune(v))), nil
+		case []interface{}:
+			return int64(len(v)), nil
+		}
+		return nil, typeMismatch("a path", argument)
+	},
+	"size": func(argument interface{}) (interface{}, error) {
+		switch v := argument.(type) {
+		case string:
+			return int64(len([]rune(v))), nil
+		case []interface{}:
+			return int64(len(v)), nil
+		}
+		return nil, typeMismatch("a list or a string", argument)
+	},
+	"labels": func(argument interface{}) (interface{}, error) {
+		n, isNode := argument.(*node)
+		if !isNode {
+			return nil, typeMismatch("a node", argument)
+		}
+		labels := make([]interface{}, len(n.labels))
+		for index, label := range n.labels {
+			labels[index] = label
+		}
+		return labels, nil
+	},
+	"keys": func(argument interface{}) (interface{}, error) {
+		properties, err := propertiesOf(argument)
+		if err != nil {
+			return nil, err
+		}
+		keys := []interface{}{}
+		for _, key := range sortedKeys(properties) {
+			keys = append(keys, key)
+		}
+		return keys, nil
+	},
+	"properties": func(argument interface{}) (interface{}, error) {
+		properties, err := propertiesOf(argument)
+		if err != nil {
+			return nil, err
+		}
+		return copyProperties(properties), nil
+	},
+	"exists": func(argument interface{}) (interface{}, error) {
+		return true, nil
+	},
+	"head": func(argument interface{}) (interface{}, error) {
+		list, isList := argument.([]interface{})
+		if !isList {
+			return nil, typeMismatch("a list", argument)
+		}
+		if len(list) == 0 {
+			return nil, nil
+		}
+		return list[0], nil
+	},
+	"last": func(argument interface{}) (interface{}, error) {
+		list, isList := argument.([]interface{})
+		if !isList {
+			return nil, typeMismatch("a list", argument)
+		}
+		if len(list) == 0 {
+			return nil, nil
+		}
+		return list[len(list)-1], nil
+	},
+	"tolower": func(argument interface{}) (interface{}, error) {
+		str, isString := argument.(string)
+		if !isString {
+			return nil, typeMismatch("a string", argument)
+		}
+		return strings.ToLower(str), nil
+	},
+	"toupper": func(argument interface{}) (interface{}, error) {
+		str, isString := argument.(string)
+		if !isString {
+			return nil, typeMismatch("a string", argument)
+		}
+		return strings.ToUpper(str), nil
+	},
+	"tostring": func(argument interface{}) (interface{}, error) {
+		switch argument.(type) {
+		case string, int64, float64, bool:
+			return toString(argument), nil
+		}
+		return nil, typeMismatch("a string, a number or a boolean", argument)
+	},
+	"tointeger": func(argument interface{}) (interface{}, error) {
+		switch v := argument.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		case string:
+			if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				return i, nil
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return int64(f), nil
+			}
+			return nil, nil
+		}
+		return nil, typeMismatch("a string or a number", argument)
+	},
+	"tofloat": func(argument interface{}) (interface{}, error) {
+		switch v := argument.(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, nil
+			}
+			return nil, nil
+		}
+		return nil, typeMismatch("a string or a number", argument)
+	},
+}
+
+func typeMismatch(expected string, value interface{}) error {
+	return errors.New("Type mismatch: expected " + expected + " but was " + typeName(value))
+}
+
+//relationshipNode returns the start or the end node of the relationship argument
+func relationshipNode(s *scope, argument interface{}, start bool) (interface{}, error) {
+	r, isRelationship := argument.(*relationship)
+	if !isRelationship {
+		return nil, typeMismatch("a relationship", argument)
+	}
+	if start {
+		return s.execution.node(r.start), nil
+	}
+	return s.execution.node(r.end), nil
+}
+
+func propertiesOf(argument interface{}) (map[string]interface{}, error) {
+	switch v := argument.(type) {
+	case *node:
+		return v.props, nil
+	case *relationship:
+		return v.props, nil
+	case map[string]interface{}:
+		return v, nil
+	}
+	return nil, typeMismatch("a map, a node or a relationship", argument)
+}
+
+func evaluateRange(arguments []interface{}) (interface{}, error) {
+	if len(arguments) < 2 || len(arguments) > 3 {
+		return nil, errors.New("Function range() expects two or three arguments")
+	}
+	bounds := [3]int64{0, 0, 1}
+	for index, argument := range arguments {
+		value, isInteger := argument.(int64)
+		if !isInteger {
+			return nil, typeMismatch("an Integer", argument)
+		}
+		bounds[index] = value
+	}
+	from, to, step := bounds[0], bounds[1], bounds[2]
+	if step == 0 {
+		return nil, errors.New("Step argument to range() can't be 0")
+	}
+	list := []interface{}{}
+	for i := from; (step > 0 && i <= to) || (step < 0 && i >= to); i += step {
+		list = append(list, i)
+	}
+	return list, nil
+}
+
+//aggregation accumulates the values of an aggregating function over the rows of a group
+type aggregation struct {
+	call     *functionCall
+	values   []interface{}
+	distinct map[string]bool
+	count    int64
+}
+
+func newAggregation(call *functionCall) *aggregation {
+	return &aggregation{call: call, distinct: map[string]bool{}}
+}
+
+func (a *aggregation) add(s *scope) error {
+	if a.call.star {
+		a.count++
+		return nil
+	}
+	if len(a.call.arguments) != 1 {
+		return errors.New("Function " + a.call.name + "() expects one argument")
+	}
+	value, err := a.call.arguments[0].evaluate(s)
+	if err != nil || value == nil {
+		return err
+	}
+	if a.call.distinct {
+		key := valueKey(value)
+		if a.distinct[key] {
+			return nil
+		}
+		a.distinct[key] = true
+	}
+	a.count++
+	a.values = append(a.values, value)
+	return nil
+}
+
+func (a *aggregation) result() (interface{}, error) {
+	switch a.call.name {
+	case "count":
+		return a.count, nil
+	case "collect":
+		return append([]interface{}{}, a.values...), nil
+	case "min", "max":
+		if len(a.values) == 0 {
+			return nil, nil
+		}
+		values := append([]interface{}{}, a.values...)
+		sort.SliceStable(values, func(i, j int) bool { return order(values[i], values[j]) < 0 })
+		if a.call.name == "min" {
+			return values[0], nil
+		}
+		return values[len(values)-1], nil
+	}
+
+	var (
+		integerSum int64
+		floatSum   float64
+		isFloat    bool
+	)
+	for _, value := range a.values {
+		switch v := value.(type) {
+		case int64:
+			integerSum += v
+			floatSum += float64(v)
+		case float64:
+			floatSum += v
+			isFloat = true
+		default:
+			return nil, typeMismatch("a number", value)
+		}
+	}
+	if a.call.name == "avg" {
+		if len(a.values) == 0 {
+			return nil, nil
+		}
+		return floatSum / float64(len(a.values)), nil
+	}
+	if isFloat {
+		return floatSum, nil
+	}
+	return integerSum, nil
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"sort"
+)
+
+type node struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+type relationship struct {
+	id      int64
+	start   int64
+	end     int64
+	relType string
+	props   map[string]interface{}
+}
+
+//path is a matched path. relationships[i] connects nodes[i] and nodes[i+1]
+type path struct {
+	nodes         []*node
+	relationships []*relationship
+}
+
+func (n *node) hasLabel(label string) bool {
+	for _, l := range n.labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) clone() *node {
+	return &node{n.id, append([]string{}, n.labels...), copyProperties(n.props)}
+}
+
+func (r *relationship) clone() *relationship {
+	return &relationship{r.id, r.start, r.end, r.relType, copyProperties(r.props)}
+}
+
+func copyProperties(props map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		copied[key] = value
+	}
+	return copied
+}
+
+//graph holds nodes, relationships and the relationships of every node
+type graph struct {
+	nodes             map[int64]*node
+	relationships     map[int64]*relationship
+	nodeRelationships map[int64]map[int64]bool
+}
+
+func newGraph() *graph {
+	return &graph{
+		nodes:             map[int64]*node{},
+		relationships:     map[int64]*relationship{},
+		nodeRelationships: map[int64]map[int64]bool{}}
+}
+
+func (g *graph) clone() *graph {
+	c := &graph{
+		nodes:             make(map[int64]*node, len(g.nodes)),
+		relationships:     make(map[int64]*relationship, len(g.relationships)),
+		nodeRelationships: make(map[int64]map[int64]bool, len(g.nodeRelationships))}
+	for id, n := range g.nodes {
+		c.nodes[id] = n.clone()
+	}
+	for id, r := range g.relationships {
+		c.relationships[id] = r.clone()
+	}
+	for id, relationships := range g.nodeRelationships {
+		c.nodeRelationships[id] = make(map[int64]bool, len(relationships))
+		for relationshipID := range relationships {
+			c.nodeRelationships[id][relationshipID] = true
+		}
+	}
+	return c
+}
+
+func (g *graph) putNode(n *node) {
+	g.nodes[n.id] = n
+	if g.nodeRelationships[n.id] == nil {
+		g.nodeRelationships[n.id] = map[int64]bool{}
+	}
+}
+
+func (g *graph) removeNode(id int64) {
+	delete(g.nodes, id)
+	delete(g.nodeRelationships, id)
+}
+
+func (g *graph) putRelationship(r *relationship) {
+	g.relationships[r.id] = r
+	for _, nodeID := range [2]int64{r.start, r.end} {
+		if g.nodeRelationships[nodeID] == nil {
+			g.nodeRelationships[nodeID] = map[int64]bool{}
+		}
+		g.nodeRelationships[nodeID][r.id] = true
+	}
+}
+
+func (g *graph) removeRelationship(id int64) {
+	if r := g.relationships[id]; r != nil {
+		delete(g.nodeRelationships[r.start], id)
+		delete(g.nodeRelationships[r.end], id)
+	}
+	delete(g.relationships, id)
+}
+
+//relationshipsOf returns the relationships of the node with id, in the order of their IDs
+func (g *graph) relationshipsOf(id int64) []*relationship {
+	ids := sortedIDs(g.nodeRelationships[id])
+	relationships := make([]*relationship, 0, len(ids))
+	for _, relationshipID := range ids {
+		relationships = append(relationships, g.relationships[relationshipID])
+	}
+	return relationships
+}
+
+//nodesWithLabel returns the nodes with label, or all the nodes when label is empty, in the order of their IDs
+func (g *graph) nodesWithLabel(label string) []*node {
+	var nodes []*node
+	for _, n := range g.nodes {
+		if label == "" || n.hasLabel(label) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].id < nodes[j].id
+	})
+	return nodes
+}
+
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identifierToken
+	quotedIdentifierToken
+	parameterToken
+	integerToken
+	floatToken
+	stringToken
+	symbolToken
+)
+
+//token is a lexeme of a statement. begin and end are its offsets in the statement
+type token struct {
+	kind  tokenKind
+	text  string
+	begin int
+	end   int
+}
+
+//symbols are the punctuation and operator lexemes, longest first
+var symbols = []string{"..", "<>", "<=", ">=", "=~", "+=", "(", ")", "[", "]", "{", "}", ",", ":", ".", "|", "=", "<", ">", "+", "-", "*", "/", "%", "$", ";"}
+
+func tokenize(statement string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(statement)
+		offset = 0
+	)
+	//offsets are byte offsets of statement, for slicing the text of expressions
+	byteOffset := func(index int) int {
+		return len(string(runes[:index]))
+	}
+
+	for offset < len(runes) {
+		r := runes[offset]
+		begin := offset
+		switch {
+		case unicode.IsSpace(r):
+			offset++
+			continue
+		case r == '/' && offset+1 < len(runes) && runes[offset+1] == '/':
+			for offset < len(runes) && runes[offset] != '\n' {
+				offset++
+			}
+			continue
+		case isIdentifierStart(r):
+			for offset < len(runes) && isIdentifierPart(runes[offset]) {
+				offset++
+			}
+			tokens = append(tokens, token{identifierToken, string(runes[begin:offset]), byteOffset(begin), byteOffset(offset)})
+		case r == '`':
+			offset++
+			for offset < len(runes) && runes[offset] != '`' {
+				offset++
+			}
+			if offset == len(runes) {
+				return nil, errors.New("Unterminated quoted identifier in statement")
+			}
+			offset++
+			tokens = append(tokens, token{quotedIdentifierToken, string(runes[begin+1 : offset-1]), byteOffset(begin), byteOffset(offset)})
+		case r == '$':
+			offset++
+			for offset < len(runes) && isIdentifierPart(runes[offset]) {
+				offset++
+			}
+			if offset == begin+1 {
+				return nil, errors.New("Missing parameter name in statement")
+			}
+			tokens = append(tokens, token{parameterToken, string(runes[begin+1 : offset]), byteOffset(begin), byteOffset(offset)})
+		case unicode.IsDigit(r):
+			kind := integerToken
+			for offset < len(runes) && unicode.IsDigit(runes[offset]) {
+				offset++
+			}
+			//A dot followed by a digit is a fraction. Otherwise, it's a range as in *0..2
+			if offset+1 < len(runes) && runes[offset] == '.' && unicode.IsDigit(runes[offset+1]) {
+				kind = floatToken
+				offset++
+				for offset < len(runes) && unicode.IsDigit(runes[offset]) {
+					offset++
+				}
+			}
+			tokens = append(tokens, token{kind, string(runes[begin:offset]), byteOffset(begin), byteOffset(offset)})
+		case r == '\'' || r == '"':
+			var (
+				text   strings.Builder
+				closed bool
+			)
+			for offset++; offset < len(runes); offset++ {
+				if runes[offset] == r {
+					closed = true
+					offset++
+					break
+				}
+				if runes[offset] == '\\' && offset+1 < len(runes) {
+					offset++
+					switch runes[offset] {
+					case 'n':
+						text.WriteRune('\n')
+					case 't':
+						text.WriteRune('\t')
+					default:
+						text.WriteRune(runes[offset])
+					}
+					continue
+				}
+				text.WriteRune(runes[offset])
+			}
+			if !closed {
+				return nil, errors.New("Unterminated string literal in statement")
+			}
+			tokens = append(tokens, token{stringToken, text.String(), byteOffset(begin), byteOffset(offset)})
+		default:
+			symbol := matchSymbol(runes[offset:])
+			if symbol == "" {
+				return nil, errors.New("Unexpected character '" + string(r) + "' in statement")
+			}
+			offset += len([]rune(symbol))
+			tokens = append(tokens, token{symbolToken, symbol, byteOffset(begin), byteOffset(offset)})
+		}
+	}
+	return append(tokens, token{kind: endToken, begin: len(statement), end: len(statement)}), nil
+}
+
+func matchSymbol(runes []rune) string {
+	for _, symbol := range symbols {
+		if strings.HasPrefix(string(runes[:min(len(runes), 2)]), symbol) {
+			return symbol
+		}
+	}
+	return ""
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,320 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+)
+
+//matcher finds the subgraphs matching patterns. Like in Neo4j, a relationship is matched at most once by the
+//patterns of a clause
+type matcher struct {
+	scope    *scope
+	bindings map[string]interface{}
+	used     map[int64]bool
+}
+
+func newMatcher(s *scope) *matcher {
+	return &matcher{scope: s, bindings: map[string]interface{}{}, used: map[int64]bool{}}
+}
+
+func (m *matcher) lookup(name string) (interface{}, bool) {
+	if value, bound := m.bindings[name]; bound {
+		return value, true
+	}
+	return m.scope.lookup(name)
+}
+
+//match calls emit with the bindings of every match of patterns
+func (m *matcher) match(patterns []*pattern, emit func() error) error {
+	if len(patterns) == 0 {
+		return emit()
+	}
+	return m.matchPattern(patterns[0], func() error {
+		return m.match(patterns[1:], emit)
+	})
+}
+
+//bind binds name to value, or checks that value is the value name is already bound to
+func (m *matcher) bind(name string, value interface{}, next func() error) error {
+	if name == "" {
+		return next()
+	}
+	if bound, isBound := m.lookup(name); isBound {
+		if isTrue(equals(bound, value)) {
+			return next()
+		}
+		return nil
+	}
+	m.bindings[name] = value
+	err := next()
+	delete(m.bindings, name)
+	return err
+}
+
+//matchProperties tells whether the properties of an entity match the properties of a pattern
+func (m *matcher) matchProperties(properties *mapLiteral, props map[string]interface{}) (bool, error) {
+	if properties == nil {
+		return true, nil
+	}
+	expected, err := evaluateProperties(properties, m.scope.with(m.bindings))
+	if err != nil {
+		return false, err
+	}
+	for key, value := range expected {
+		if !isTrue(equals(props[key], value)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *matcher) bindNode(np *nodePattern, n *node, next func() error) error {
+	for _, label := range np.labels {
+		if !n.hasLabel(label) {
+			return nil
+		}
+	}
+	if matched, err := m.matchProperties(np.properties, n.props); err != nil || !matched {
+		return err
+	}
+	return m.bind(np.variable, n, next)
+}
+
+//anchor returns the index of the node of p the match starts from, and the candidate nodes at that index.
+//Matches start from bound nodes, then from the nodes of bound relationships, then from labeled nodes
+func (m *matcher) anchor(p *pattern) (int, []*node, error) {
+	g := m.scope.execution.graph
+	for index, np := range p.nodes {
+		if np.variable == "" {
+			continue
+		}
+		if value, bound := m.lookup(np.variable); bound {
+			switch v := value.(type) {
+			case nil:
+				return index, nil, nil
+			case *node:
+				if g.nodes[v.id] == nil {
+					return index, nil, nil
+				}
+				return index, []*node{v}, nil
+			}
+			return 0, nil, errors.New("Type mismatch: " + np.variable + " defined with conflicting type " + typeName(value) + " (expected Node)")
+		}
+	}
+	for index, rp := range p.relationships {
+		if rp.variable == "" || rp.variableLength {
+			continue
+		}
+		if value, bound := m.lookup(rp.variable); bound {
+			switch v := value.(type) {
+			case nil:
+				return index, nil, nil
+			case *relationship:
+				var candidates []*node
+				for _, id := range [2]int64{v.start, v.end} {
+					if n := g.nodes[id]; n != nil && (len(candidates) == 0 || candidates[0] != n) {
+						candidates = append(candidates, n)
+					}
+				}
+				return index, candidates, nil
+			}
+			return 0, nil, errors.New("Type mismatch: " + rp.variable + " defined with conflicting type " + typeName(value) + " (expected Relationship)")
+		}
+	}
+	for index, np := range p.nodes {
+		if len(np.labels) > 0 {
+			return index, g.nodesWithLabel(np.labels[0]), nil
+		}
+	}
+	return 0, g.nodesWithLabel(""), nil
+}
+
+func (m *matcher) matchPattern(p *pattern, emit func() error) error {
+	anchor, candidates, err := m.anchor(p)
+	if err != nil {
+		return err
+	}
+	var (
+		nodes = make([]*node, len(p.nodes))
+		hops  = make([][]*relationship, len(p.relationships))
+	)
+	for _, candidate := range candidates {
+		n := candidate
+		if err = m.bindNode(p.nodes[anchor], n, func() error {
+			nodes[anchor] = n
+			return m.expandRight(p, anchor, nodes, hops, func() error {
+				return m.expandLeft(p, anchor, nodes, hops, func() error {
+					if p.pathVariable == "" {
+						return emit()
+					}
+					return m.bind(p.pathVariable, m.newPath(nodes[0], hops), emit)
+				})
+			})
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//expandRight matches the relationships and the nodes of p after the node at index
+func (m *matcher) expandRight(p *pattern, index int, nodes []*node, hops [][]*relationship, next func() error) error {
+	if index == len(p.nodes)-1 {
+		return next()
+	}
+	rp := p.relationships[index]
+	return m.traverse(rp, nodes[index], true, func(relationships []*relationship, end *node) error {
+		hops[index] = relationships
+		return m.bindRelationships(rp, relationships, func() error {
+			return m.bindNode(p.nodes[index+1], end, func() error {
+				nodes[index+1] = end
+				return m.expandRight(p, index+1, nodes, hops, next)
+			})
+		})
+	})
+}
+
+//expandLeft matches the relationships and the nodes of p before the node at index
+func (m *matcher) expandLeft(p *pattern, index int, nodes []*node, hops [][]*relationship, next func() error) error {
+	if index == 0 {
+		return next()
+	}
+	rp := p.relationships[index-1]
+	return m.traverse(rp, nodes[index], false, func(relationships []*relationship, end *node) error {
+		reversed := make([]*relationship, len(relationships))
+		for i, r := range relationships {
+			reversed[len(relationships)-1-i] = r
+		}
+		hops[index-1] = reversed
+		return m.bindRelationships(rp, reversed, func() error {
+			return m.bindNode(p.nodes[index-1], end, func() error {
+				nodes[index-1] = end
+				return m.expandLeft(p, index-1, nodes, hops, next)
+			})
+		})
+	})
+}
+
+//bindRelationships binds the variable of rp to the matched relationship, or to the list of matched relationships
+//of a variable length pattern
+func (m *matcher) bindRelationships(rp *relationshipPattern, relationships []*relationship, next func() error) error {
+	if !rp.variableLength {
+		return m.bind(rp.variable, relationships[0], next)
+	}
+	list := make([]interface{}, len(relationships))
+	for index, r := range relationships {
+		list[index] = r
+	}
+	return m.bind(rp.variable, list, next)
+}
+
+//traverse calls emit with the relationships of every path matching rp from the node from, and the node it ends at.
+//The path is followed backward, from the right node of rp, when forward is false
+func (m *matcher) traverse(rp *relationshipPattern, from *node, forward bool, emit func(relationships []*relationship, end *node) error) error {
+	minHops, maxHops := 1, 1
+	if rp.variableLength {
+		minHops, maxHops = rp.minHops, rp.maxHops
+	}
+	d := rp.direction
+	if !forward {
+		switch d {
+		case outgoing:
+			d = incoming
+		case incoming:
+			d = outgoing
+		}
+	}
+	g := m.scope.execution.graph
+
+	var walk func(current *node, relationships []*relationship) error
+	walk = func(current *node, relationships []*relationship) error {
+		if len(relationships) >= minHops {
+			if err := emit(relationships, current); err != nil {
+				return err
+			}
+		}
+		if maxHops != unbounded && len(relationships) >= maxHops {
+			return nil
+		}
+		for _, r := range g.relationshipsOf(current.id) {
+			if m.used[r.id] || !matchesType(rp, r) {
+				continue
+			}
+			var otherID int64
+			switch {
+			case d != incoming && r.start == current.id:
+				otherID = r.end
+			case d != outgoing && r.end == current.id:
+				otherID = r.start
+			default:
+				continue
+			}
+			matched, err := m.matchProperties(rp.properties, r.props)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+			m.used[r.id] = true
+			err = walk(g.nodes[otherID], append(relationships[:len(relationships):len(relationships)], r))
+			delete(m.used, r.id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(from, nil)
+}
+
+func matchesType(rp *relationshipPattern, r *relationship) bool {
+	if len(rp.types) == 0 {
+		return true
+	}
+	for _, relType := range rp.types {
+		if relType == r.relType {
+			return true
+		}
+	}
+	return false
+}
+
+//newPath returns the path starting at start made of hops
+func (m *matcher) newPath(start *node, hops [][]*relationship) *path {
+	p := &path{nodes: []*node{start}}
+	current := start
+	for _, relationships := range hops {
+		for _, r := range relationships {
+			otherID := r.start
+			if r.start == current.id {
+				otherID = r.end
+			}
+			current = m.scope.execution.graph.nodes[otherID]
+			p.nodes = append(p.nodes, current)
+			p.relationships = append(p.relationships, r)
+		}
+	}
+	return p
+}
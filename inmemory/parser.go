@@ -0,0 +1,1104 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//reservedWords can't be used as variables without quoting
+var reservedWords = map[string]bool{
+	"MATCH": true, "OPTIONAL": true, "CREATE": true, "MERGE": true, "SET": true, "DELETE": true, "DETACH": true,
+	"REMOVE": true, "UNWIND": true, "WITH": true, "RETURN": true, "WHERE": true, "ORDER": true, "BY": true,
+	"SKIP": true, "LIMIT": true, "AND": true, "OR": true, "XOR": true, "NOT": true, "AS": true, "IN": true,
+	"STARTS": true, "ENDS": true, "CONTAINS": true, "IS": true, "ON": true, "CASE": true, "WHEN": true,
+	"THEN": true, "ELSE": true, "END": true, "DISTINCT": true, "ASC": true, "DESC": true, "ASCENDING": true,
+	"DESCENDING": true}
+
+var quantifiers = map[string]bool{"all": true, "any": true, "none": true, "single": true}
+
+var comparisonSymbols = map[string]bool{"=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true, "=~": true}
+
+type parser struct {
+	statement string
+	tokens    []token
+	position  int
+}
+
+func parse(cypher string) (*statement, error) {
+	tokens, err := tokenize(cypher)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{statement: cypher, tokens: tokens}
+	return p.parseStatement()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.position+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.position+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != endToken {
+		p.position++
+	}
+	return t
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.kind == identifierToken && strings.EqualFold(t.text, keyword)
+}
+
+func isSymbol(t token, symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+//acceptKeywords consumes keywords when the next tokens are keywords
+func (p *parser) acceptKeywords(keywords ...string) bool {
+	for index, keyword := range keywords {
+		if !isKeyword(p.peekAt(index), keyword) {
+			return false
+		}
+	}
+	p.position += len(keywords)
+	return true
+}
+
+func (p *parser) expectKeywords(keywords ...string) error {
+	if !p.acceptKeywords(keywords...) {
+		return p.unexpected(strings.Join(keywords, " "))
+	}
+	return nil
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if isSymbol(p.peek(), symbol) {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected("'" + symbol + "'")
+	}
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	found := "end of statement"
+	if t.kind != endToken {
+		found = "'" + p.statement[t.begin:t.end] + "'"
+	}
+	return errors.New("Invalid input " + found + " at offset " + strconv.Itoa(t.begin) + ": expected " + expected)
+}
+
+//parseName parses a variable, label, type or property name
+func (p *parser) parseName() (string, error) {
+	t := p.peek()
+	if t.kind != identifierToken && t.kind != quotedIdentifierToken {
+		return "", p.unexpected("a name")
+	}
+	p.position++
+	return t.text, nil
+}
+
+//parseVariable parses a variable, which can't be a reserved word unless it's quoted
+func (p *parser) parseVariable() (string, error) {
+	t := p.peek()
+	if t.kind == identifierToken && reservedWords[strings.ToUpper(t.text)] {
+		return "", p.unexpected("a variable")
+	}
+	return p.parseName()
+}
+
+func (p *parser) isVariable(t token) bool {
+	return t.kind == quotedIdentifierToken || (t.kind == identifierToken && !reservedWords[strings.ToUpper(t.text)])
+}
+
+func (p *parser) parseStatement() (*statement, error) {
+	if isKeyword(p.peek(), "CREATE") && (isKeyword(p.peekAt(1), "INDEX") || isKeyword(p.peekAt(1), "CONSTRAINT")) ||
+		isKeyword(p.peek(), "DROP") {
+		return p.parseSchemaCommand()
+	}
+
+	s := &statement{}
+	for {
+		p.acceptSymbol(";")
+		if p.peek().kind == endToken {
+			break
+		}
+		c, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		s.clauses = append(s.clauses, c)
+	}
+	if len(s.clauses) == 0 {
+		return nil, errors.New("Empty statement")
+	}
+	for index, c := range s.clauses {
+		if projection, isProjection := c.(*projectionClause); isProjection && projection.isReturn && index != len(s.clauses)-1 {
+			return nil, errors.New("RETURN can only be used at the end of a statement")
+		}
+	}
+	return s, nil
+}
+
+//parseSchemaCommand parses CREATE INDEX ON :Label(p1, p2), CREATE CONSTRAINT ON (a:Label) ASSERT a.p IS UNIQUE
+//and their DROP counterparts
+func (p *parser) parseSchemaCommand() (*statement, error) {
+	var (
+		command = schemaCommand{}
+		drop    = p.acceptKeywords("DROP")
+		err     error
+	)
+	if !drop {
+		if err = p.expectKeywords("CREATE"); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.acceptKeywords("INDEX", "ON"):
+		command.kind = createIndex
+		if drop {
+			command.kind = dropIndex
+		}
+		if err = p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		if command.label, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		for {
+			var property string
+			if property, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			command.properties = append(command.properties, property)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	case p.acceptKeywords("CONSTRAINT", "ON"):
+		command.kind = createUniqueConstraint
+		if drop {
+			command.kind = dropUniqueConstraint
+		}
+		var ref, property string
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		if ref, err = p.parseVariable(); err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		if command.label, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeywords("ASSERT"); err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != identifierToken || t.text != ref {
+			return nil, errors.New("Constraint must assert a property of '" + ref + "'")
+		}
+		if err = p.expectSymbol("."); err != nil {
+			return nil, err
+		}
+		if property, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeywords("IS", "UNIQUE"); err != nil {
+			return nil, err
+		}
+		command.properties = []string{property}
+	default:
+		return nil, p.unexpected("INDEX or CONSTRAINT")
+	}
+	p.acceptSymbol(";")
+	if p.peek().kind != endToken {
+		return nil, p.unexpected("end of statement")
+	}
+	return &statement{schema: command}, nil
+}
+
+func (p *parser) parseClause() (clause, error) {
+	switch {
+	case p.acceptKeywords("OPTIONAL", "MATCH"):
+		return p.parseMatch(true)
+	case p.acceptKeywords("MATCH"):
+		return p.parseMatch(false)
+	case p.acceptKeywords("CREATE"):
+		patterns, err := p.parsePatterns()
+		if err != nil {
+			return nil, err
+		}
+		return &createClause{patterns}, nil
+	case p.acceptKeywords("MERGE"):
+		return p.parseMerge()
+	case p.acceptKeywords("SET"):
+		items, err := p.parseSetItems()
+		if err != nil {
+			return nil, err
+		}
+		return &setClause{items}, nil
+	case p.acceptKeywords("REMOVE"):
+		return p.parseRemove()
+	case p.acceptKeywords("DETACH", "DELETE"):
+		return p.parseDelete(true)
+	case p.acceptKeywords("DELETE"):
+		return p.parseDelete(false)
+	case p.acceptKeywords("UNWIND"):
+		return p.parseUnwind()
+	case p.acceptKeywords("WITH"):
+		return p.parseProjection(false)
+	case p.acceptKeywords("RETURN"):
+		return p.parseProjection(true)
+	}
+	return nil, p.unexpected("a clause")
+}
+
+func (p *parser) parseMatch(optional bool) (clause, error) {
+	var (
+		c   = &matchClause{optional: optional}
+		err error
+	)
+	if c.patterns, err = p.parsePatterns(); err != nil {
+		return nil, err
+	}
+	if p.acceptKeywords("WHERE") {
+		if c.where, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (p *parser) parseMerge() (clause, error) {
+	var (
+		c   = &mergeClause{}
+		err error
+	)
+	if c.pattern, err = p.parsePattern(); err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.acceptKeywords("ON", "CREATE", "SET"):
+			var items []*setItem
+			if items, err = p.parseSetItems(); err != nil {
+				return nil, err
+			}
+			c.onCreate = append(c.onCreate, items...)
+		case p.acceptKeywords("ON", "MATCH", "SET"):
+			var items []*setItem
+			if items, err = p.parseSetItems(); err != nil {
+				return nil, err
+			}
+			c.onMatch = append(c.onMatch, items...)
+		default:
+			return c, nil
+		}
+	}
+}
+
+func (p *parser) parseSetItems() ([]*setItem, error) {
+	var items []*setItem
+	for {
+		var (
+			item = &setItem{}
+			err  error
+		)
+		if item.variable, err = p.parseVariable(); err != nil {
+			return nil, err
+		}
+		switch {
+		case isSymbol(p.peek(), ":"):
+			item.kind = setLabels
+			if item.labels, err = p.parseLabels(); err != nil {
+				return nil, err
+			}
+		case p.acceptSymbol("+="):
+			item.kind = mergeProperties
+			if item.value, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		case p.acceptSymbol("="):
+			item.kind = replaceProperties
+			if item.value, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		case p.acceptSymbol("."):
+			item.kind = setProperty
+			if item.property, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			if err = p.expectSymbol("="); err != nil {
+				return nil, err
+			}
+			if item.value, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.unexpected("a property or a label to set")
+		}
+		items = append(items, item)
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *parser) parseRemove() (clause, error) {
+	c := &setClause{}
+	for {
+		var (
+			item = &setItem{}
+			err  error
+		)
+		if item.variable, err = p.parseVariable(); err != nil {
+			return nil, err
+		}
+		switch {
+		case isSymbol(p.peek(), ":"):
+			item.kind = removeLabels
+			if item.labels, err = p.parseLabels(); err != nil {
+				return nil, err
+			}
+		case p.acceptSymbol("."):
+			item.kind = removeProperty
+			if item.property, err = p.parseName(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.unexpected("a property or a label to remove")
+		}
+		c.items = append(c.items, item)
+		if !p.acceptSymbol(",") {
+			return c, nil
+		}
+	}
+}
+
+func (p *parser) parseDelete(detach bool) (clause, error) {
+	c := &deleteClause{detach: detach}
+	for {
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		c.expressions = append(c.expressions, e)
+		if !p.acceptSymbol(",") {
+			return c, nil
+		}
+	}
+}
+
+func (p *parser) parseUnwind() (clause, error) {
+	var (
+		c   = &unwindClause{}
+		err error
+	)
+	if c.list, err = p.parseExpression(); err != nil {
+		return nil, err
+	}
+	if err = p.expectKeywords("AS"); err != nil {
+		return nil, err
+	}
+	if c.variable, err = p.parseVariable(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (p *parser) parseProjection(isReturn bool) (clause, error) {
+	var (
+		c   = &projectionClause{isReturn: isReturn}
+		err error
+	)
+	c.distinct = p.acceptKeywords("DISTINCT")
+	hasItems := true
+	if p.acceptSymbol("*") {
+		c.star = true
+		hasItems = p.acceptSymbol(",")
+	}
+	for hasItems {
+		item := &projectionItem{}
+		begin := p.peek().begin
+		if item.expression, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		item.name = strings.TrimSpace(p.statement[begin:p.tokens[p.position-1].end])
+		switch e := item.expression.(type) {
+		case *variable:
+			item.name = e.name
+		case *mapProjection:
+			item.name = e.variable
+		}
+		if p.acceptKeywords("AS") {
+			if item.name, err = p.parseName(); err != nil {
+				return nil, err
+			}
+		}
+		c.items = append(c.items, item)
+		hasItems = p.acceptSymbol(",")
+	}
+
+	if p.acceptKeywords("ORDER", "BY") {
+		for {
+			item := &sortItem{}
+			if item.expression, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+			if p.acceptKeywords("DESC") || p.acceptKeywords("DESCENDING") {
+				item.descending = true
+			} else if !p.acceptKeywords("ASC") {
+				p.acceptKeywords("ASCENDING")
+			}
+			c.orderBy = append(c.orderBy, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeywords("SKIP") {
+		if c.skip, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeywords("LIMIT") {
+		if c.limit, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	if !isReturn && p.acceptKeywords("WHERE") {
+		if c.where, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (p *parser) parseLabels() ([]string, error) {
+	var labels []string
+	for p.acceptSymbol(":") {
+		label, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (p *parser) parsePatterns() ([]*pattern, error) {
+	var patterns []*pattern
+	for {
+		pattern, err := p.parsePattern()
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+		if !p.acceptSymbol(",") {
+			return patterns, nil
+		}
+	}
+}
+
+func (p *parser) parsePattern() (*pattern, error) {
+	var (
+		result = &pattern{}
+		err    error
+	)
+	if p.isVariable(p.peek()) && isSymbol(p.peekAt(1), "=") {
+		result.pathVariable = p.next().text
+		p.next()
+	}
+	for {
+		var n *nodePattern
+		if n, err = p.parseNodePattern(); err != nil {
+			return nil, err
+		}
+		result.nodes = append(result.nodes, n)
+		if !isSymbol(p.peek(), "-") && !(isSymbol(p.peek(), "<") && isSymbol(p.peekAt(1), "-")) {
+			return result, nil
+		}
+		var r *relationshipPattern
+		if r, err = p.parseRelationshipPattern(); err != nil {
+			return nil, err
+		}
+		result.relationships = append(result.relationships, r)
+	}
+}
+
+func (p *parser) parseNodePattern() (*nodePattern, error) {
+	var (
+		n   = &nodePattern{}
+		err error
+	)
+	if err = p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	if p.isVariable(p.peek()) {
+		n.variable = p.next().text
+	}
+	if n.labels, err = p.parseLabels(); err != nil {
+		return nil, err
+	}
+	if isSymbol(p.peek(), "{") {
+		if n.properties, err = p.parseMapLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	return n, p.expectSymbol(")")
+}
+
+func (p *parser) parseRelationshipPattern() (*relationshipPattern, error) {
+	var (
+		r            = &relationshipPattern{minHops: 1, maxHops: 1}
+		leftArrowed  = p.acceptSymbol("<")
+		rightArrowed bool
+		err          error
+	)
+	if err = p.expectSymbol("-"); err != nil {
+		return nil, err
+	}
+	if p.acceptSymbol("[") {
+		if p.isVariable(p.peek()) {
+			r.variable = p.next().text
+		}
+		if p.acceptSymbol(":") {
+			for {
+				var relType string
+				if relType, err = p.parseName(); err != nil {
+					return nil, err
+				}
+				r.types = append(r.types, relType)
+				if !p.acceptSymbol("|") {
+					break
+				}
+				p.acceptSymbol(":")
+			}
+		}
+		if p.acceptSymbol("*") {
+			r.variableLength = true
+			r.minHops, r.maxHops = 1, unbounded
+			if p.peek().kind == integerToken {
+				r.minHops, _ = strconv.Atoi(p.next().text)
+				r.maxHops = r.minHops
+			}
+			if p.acceptSymbol("..") {
+				r.maxHops = unbounded
+				if p.peek().kind == integerToken {
+					r.maxHops, _ = strconv.Atoi(p.next().text)
+				}
+			}
+		}
+		if isSymbol(p.peek(), "{") {
+			if r.properties, err = p.parseMapLiteral(); err != nil {
+				return nil, err
+			}
+		}
+		if err = p.expectSymbol("]"); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.expectSymbol("-"); err != nil {
+		return nil, err
+	}
+	rightArrowed = p.acceptSymbol(">")
+	switch {
+	case leftArrowed && !rightArrowed:
+		r.direction = incoming
+	case rightArrowed && !leftArrowed:
+		r.direction = outgoing
+	}
+	return r, nil
+}
+
+func (p *parser) parseMapLiteral() (*mapLiteral, error) {
+	m := &mapLiteral{}
+	if err := p.expectSymbol("{"); err != nil {
+		return nil, err
+	}
+	if p.acceptSymbol("}") {
+		return m, nil
+	}
+	for {
+		key, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		var value expression
+		if value, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return m, p.expectSymbol("}")
+}
+
+func (p *parser) parseExpression() (expression, error) {
+	return p.parseBinary(0)
+}
+
+//booleanLevels are the boolean operators from the lowest precedence to the highest
+var booleanLevels = []string{"OR", "XOR", "AND"}
+
+func (p *parser) parseBinary(level int) (expression, error) {
+	if level == len(booleanLevels) {
+		return p.parseNot()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeywords(booleanLevels[level]) {
+		var right expression
+		if right, err = p.parseBinary(level + 1); err != nil {
+			return nil, err
+		}
+		left = &binaryExpression{booleanLevels[level], left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if p.acceptKeywords("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var operator string
+		switch t := p.peek(); {
+		case t.kind == symbolToken && comparisonSymbols[t.text]:
+			p.next()
+			operator = t.text
+		case p.acceptKeywords("STARTS", "WITH"):
+			operator = "STARTS WITH"
+		case p.acceptKeywords("ENDS", "WITH"):
+			operator = "ENDS WITH"
+		case p.acceptKeywords("CONTAINS"):
+			operator = "CONTAINS"
+		case p.acceptKeywords("IN"):
+			operator = "IN"
+		case p.acceptKeywords("IS", "NULL"):
+			left = &nullPredicate{left, false}
+			continue
+		case p.acceptKeywords("IS", "NOT", "NULL"):
+			left = &nullPredicate{left, true}
+			continue
+		default:
+			return left, nil
+		}
+		var right expression
+		if right, err = p.parseAdditive(); err != nil {
+			return nil, err
+		}
+		left = &binaryExpression{operator, left, right}
+	}
+}
+
+func (p *parser) parseAdditive() (expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for isSymbol(p.peek(), "+") || isSymbol(p.peek(), "-") {
+		operator := p.next().text
+		var right expression
+		if right, err = p.parseMultiplicative(); err != nil {
+			return nil, err
+		}
+		left = &binaryExpression{operator, left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isSymbol(p.peek(), "*") || isSymbol(p.peek(), "/") || isSymbol(p.peek(), "%") {
+		operator := p.next().text
+		var right expression
+		if right, err = p.parseUnary(); err != nil {
+			return nil, err
+		}
+		left = &binaryExpression{operator, left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if p.acceptSymbol("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{operand}, nil
+	}
+	p.acceptSymbol("+")
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (expression, error) {
+	subject, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.acceptSymbol("."):
+			var property string
+			if property, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			subject = &propertyAccess{subject, property}
+		case isSymbol(p.peek(), ":"):
+			var labels []string
+			if labels, err = p.parseLabels(); err != nil {
+				return nil, err
+			}
+			subject = &labelPredicate{subject, labels}
+		case p.acceptSymbol("["):
+			var from, to expression
+			if !isSymbol(p.peek(), "..") {
+				if from, err = p.parseExpression(); err != nil {
+					return nil, err
+				}
+			}
+			if p.acceptSymbol("..") {
+				if !isSymbol(p.peek(), "]") {
+					if to, err = p.parseExpression(); err != nil {
+						return nil, err
+					}
+				}
+				subject = &sliceAccess{subject, from, to}
+			} else {
+				subject = &indexAccess{subject, from}
+			}
+			if err = p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return subject, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	t := p.peek()
+	switch t.kind {
+	case integerToken:
+		p.next()
+		value, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &literal{value}, nil
+	case floatToken:
+		p.next()
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &literal{value}, nil
+	case stringToken:
+		p.next()
+		return &literal{t.text}, nil
+	case parameterToken:
+		p.next()
+		return &parameter{t.text}, nil
+	case symbolToken:
+		switch t.text {
+		case "(":
+			p.next()
+			e, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expectSymbol(")")
+		case "[":
+			return p.parseList()
+		case "{":
+			return p.parseMapLiteral()
+		}
+	case identifierToken, quotedIdentifierToken:
+		if t.kind == identifierToken {
+			switch strings.ToUpper(t.text) {
+			case "TRUE":
+				p.next()
+				return &literal{true}, nil
+			case "FALSE":
+				p.next()
+				return &literal{false}, nil
+			case "NULL":
+				p.next()
+				return &literal{nil}, nil
+			case "CASE":
+				return p.parseCase()
+			}
+			if isSymbol(p.peekAt(1), "(") {
+				return p.parseFunctionCall()
+			}
+		}
+		name, err := p.parseVariable()
+		if err != nil {
+			return nil, err
+		}
+		if isSymbol(p.peek(), "{") {
+			return p.parseMapProjection(name)
+		}
+		return &variable{name}, nil
+	}
+	return nil, p.unexpected("an expression")
+}
+
+func (p *parser) parseFunctionCall() (expression, error) {
+	name := strings.ToLower(p.next().text)
+	p.next()
+	if quantifiers[name] && p.isVariable(p.peek()) && isKeyword(p.peekAt(1), "IN") {
+		q := &quantifier{kind: name}
+		variable, list, where, err := p.parseFilter()
+		if err != nil {
+			return nil, err
+		}
+		q.variable, q.list, q.where = variable, list, where
+		if q.where == nil {
+			return nil, p.unexpected("WHERE")
+		}
+		return q, p.expectSymbol(")")
+	}
+
+	call := &functionCall{name: name}
+	if p.acceptSymbol("*") {
+		call.star = true
+		return call, p.expectSymbol(")")
+	}
+	call.distinct = p.acceptKeywords("DISTINCT")
+	if p.acceptSymbol(")") {
+		return call, nil
+	}
+	for {
+		argument, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		call.arguments = append(call.arguments, argument)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return call, p.expectSymbol(")")
+}
+
+//parseFilter parses 'variable IN list [WHERE predicate]' of quantifiers and list comprehensions
+func (p *parser) parseFilter() (string, expression, expression, error) {
+	var (
+		variable    = p.next().text
+		list, where expression
+		err         error
+	)
+	p.next()
+	if list, err = p.parseExpression(); err != nil {
+		return "", nil, nil, err
+	}
+	if p.acceptKeywords("WHERE") {
+		if where, err = p.parseExpression(); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	return variable, list, where, nil
+}
+
+//parseList parses list literals, list comprehensions and pattern comprehensions
+func (p *parser) parseList() (expression, error) {
+	var err error
+	p.next()
+	if p.isVariable(p.peek()) && isKeyword(p.peekAt(1), "IN") {
+		c := &listComprehension{}
+		if c.variable, c.list, c.where, err = p.parseFilter(); err != nil {
+			return nil, err
+		}
+		if p.acceptSymbol("|") {
+			if c.projection, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		}
+		return c, p.expectSymbol("]")
+	}
+
+	if isSymbol(p.peek(), "(") {
+		//A pattern comprehension, unless the parenthesis is a parenthesized expression of a list literal
+		position := p.position
+		if pattern, patternErr := p.parsePattern(); patternErr == nil && len(pattern.relationships) > 0 &&
+			(isSymbol(p.peek(), "|") || isKeyword(p.peek(), "WHERE")) {
+			c := &patternComprehension{pattern: pattern}
+			if p.acceptKeywords("WHERE") {
+				if c.where, err = p.parseExpression(); err != nil {
+					return nil, err
+				}
+			}
+			if err = p.expectSymbol("|"); err != nil {
+				return nil, err
+			}
+			if c.projection, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+			return c, p.expectSymbol("]")
+		}
+		p.position = position
+	}
+
+	l := &listLiteral{}
+	if p.acceptSymbol("]") {
+		return l, nil
+	}
+	for {
+		var item expression
+		if item, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return l, p.expectSymbol("]")
+}
+
+func (p *parser) parseMapProjection(name string) (expression, error) {
+	m := &mapProjection{variable: name}
+	p.next()
+	if p.acceptSymbol("}") {
+		return m, nil
+	}
+	for {
+		var (
+			key   string
+			value expression
+			err   error
+		)
+		if p.acceptSymbol(".") {
+			if key, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			value = &propertyAccess{&variable{name}, key}
+		} else {
+			if key, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			if err = p.expectSymbol(":"); err != nil {
+				return nil, err
+			}
+			if value, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return m, p.expectSymbol("}")
+}
+
+func (p *parser) parseCase() (expression, error) {
+	var (
+		c   = &caseExpression{}
+		err error
+	)
+	p.next()
+	if !isKeyword(p.peek(), "WHEN") {
+		if c.subject, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	for p.acceptKeywords("WHEN") {
+		var condition, result expression
+		if condition, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeywords("THEN"); err != nil {
+			return nil, err
+		}
+		if result, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		c.conditions = append(c.conditions, condition)
+		c.results = append(c.results, result)
+	}
+	if len(c.conditions) == 0 {
+		return nil, p.unexpected("WHEN")
+	}
+	if p.acceptKeywords("ELSE") {
+		if c.defaultCase, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expectKeywords("END")
+}
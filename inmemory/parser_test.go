@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+//describeClauses returns the keywords of the clauses of s
+func describeClauses(s *statement) []string {
+	var keywords []string
+	for _, c := range s.clauses {
+		switch c := c.(type) {
+		case *matchClause:
+			if c.optional {
+				keywords = append(keywords, "OPTIONAL MATCH")
+			} else {
+				keywords = append(keywords, "MATCH")
+			}
+		case *createClause:
+			keywords = append(keywords, "CREATE")
+		case *mergeClause:
+			keywords = append(keywords, "MERGE")
+		case *setClause:
+			switch c.items[0].kind {
+			case mergeProperties:
+				keywords = append(keywords, "SET +=")
+			case replaceProperties:
+				keywords = append(keywords, "SET =")
+			case removeProperty, removeLabels:
+				keywords = append(keywords, "REMOVE")
+			default:
+				keywords = append(keywords, "SET")
+			}
+		case *deleteClause:
+			if c.detach {
+				keywords = append(keywords, "DETACH DELETE")
+			} else {
+				keywords = append(keywords, "DELETE")
+			}
+		case *unwindClause:
+			keywords = append(keywords, "UNWIND")
+		case *projectionClause:
+			if c.isReturn {
+				keywords = append(keywords, "RETURN")
+			} else {
+				keywords = append(keywords, "WITH")
+			}
+		}
+	}
+	return keywords
+}
+
+func TestParseClauses(t *testing.T) {
+	tests := []struct {
+		cypher  string
+		clauses []string
+	}{
+		{`MATCH (n:SimpleNode) WHERE ID(n) = $id RETURN n`, []string{"MATCH", "RETURN"}},
+		{`MATCH (n:SimpleNode) WHERE ID(n) IN $ids
+		OPTIONAL MATCH p = (n)-[*0..1]-(m) RETURN p, n`, []string{"MATCH", "OPTIONAL MATCH", "RETURN"}},
+		{`MERGE (n:MergeNode {code: $code}) ON CREATE SET n.created = true ON MATCH SET n.matched = true RETURN ID(n)`, []string{"MERGE", "RETURN"}},
+		{`UNWIND $rows AS row
+		CREATE (n:SimpleNode)
+		SET n += row.properties
+		RETURN row.ref, ID(n)`, []string{"UNWIND", "CREATE", "SET +=", "RETURN"}},
+		{`MATCH (n) WHERE ID(n) = $id SET n = $properties, n:Archived`, []string{"MATCH", "SET ="}},
+		{`MATCH (n) REMOVE n.prop1, n:Archived`, []string{"MATCH", "REMOVE"}},
+		{`MATCH (n:SimpleNode) DETACH DELETE n`, []string{"MATCH", "DETACH DELETE"}},
+		{`MATCH ()-[r:SIMPLE]->() DELETE r`, []string{"MATCH", "DELETE"}},
+		{`MATCH (n:SimpleNode) WITH n ORDER BY n.prop1 SKIP 1 LIMIT 2 WHERE n.prop1 <> 'a' RETURN count(n)`, []string{"MATCH", "WITH", "RETURN"}},
+		{`MATCH (n) RETURN count(*);`, []string{"MATCH", "RETURN"}},
+	}
+	for _, test := range tests {
+		g := NewGomegaWithT(t)
+		s, err := parse(test.cypher)
+		g.Expect(err).NotTo(HaveOccurred(), test.cypher)
+		g.Expect(describeClauses(s)).To(Equal(test.clauses), test.cypher)
+	}
+}
+
+func TestParseRelationshipPatterns(t *testing.T) {
+	tests := []struct {
+		cypher         string
+		types          []string
+		direction      direction
+		variableLength bool
+		minHops        int
+		maxHops        int
+	}{
+		{`MATCH (n)-[r:SIMPLE]->(m) RETURN r`, []string{"SIMPLE"}, outgoing, false, 1, 1},
+		{`MATCH (n)<-[r:A|B]-(m) RETURN r`, []string{"A", "B"}, incoming, false, 1, 1},
+		{`MATCH (n)-[r:A|:B]-(m) RETURN r`, []string{"A", "B"}, either, false, 1, 1},
+		{`MATCH p = (n)-[*]-(m) RETURN p`, nil, either, true, 1, unbounded},
+		{`MATCH p = (n)-[*2]-(m) RETURN p`, nil, either, true, 2, 2},
+		{`MATCH p = (n)-[*0..3]->(m) RETURN p`, nil, outgoing, true, 0, 3},
+		{`MATCH p = (n)-[*1..]->(m) RETURN p`, nil, outgoing, true, 1, unbounded},
+	}
+	for _, test := range tests {
+		g := NewGomegaWithT(t)
+		s, err := parse(test.cypher)
+		g.Expect(err).NotTo(HaveOccurred(), test.cypher)
+		r := s.clauses[0].(*matchClause).patterns[0].relationships[0]
+		g.Expect(r.types).To(Equal(test.types), test.cypher)
+		g.Expect(r.direction).To(Equal(test.direction), test.cypher)
+		g.Expect(r.variableLength).To(Equal(test.variableLength), test.cypher)
+		g.Expect(r.minHops).To(Equal(test.minHops), test.cypher)
+		g.Expect(r.maxHops).To(Equal(test.maxHops), test.cypher)
+	}
+}
+
+func TestParseSchemaCommands(t *testing.T) {
+	tests := []struct {
+		cypher  string
+		command schemaCommand
+	}{
+		{`CREATE INDEX ON :SimpleNode(prop1)`, schemaCommand{createIndex, "SimpleNode", []string{"prop1"}}},
+		{`CREATE INDEX ON :SimpleNode(prop1, prop2)`, schemaCommand{createIndex, "SimpleNode", []string{"prop1", "prop2"}}},
+		{`DROP INDEX ON :SimpleNode(prop1)`, schemaCommand{dropIndex, "SimpleNode", []string{"prop1"}}},
+		{`CREATE CONSTRAINT ON (n:MergeNode) ASSERT n.code IS UNIQUE`, schemaCommand{createUniqueConstraint, "MergeNode", []string{"code"}}},
+		{`DROP CONSTRAINT ON (n:MergeNode) ASSERT n.code IS UNIQUE`, schemaCommand{dropUniqueConstraint, "MergeNode", []string{"code"}}},
+	}
+	for _, test := range tests {
+		g := NewGomegaWithT(t)
+		s, err := parse(test.cypher)
+		g.Expect(err).NotTo(HaveOccurred(), test.cypher)
+		g.Expect(s.clauses).To(BeEmpty(), test.cypher)
+		g.Expect(s.schema).To(Equal(test.command), test.cypher)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`MATCH (n RETURN n`,
+		`RETURN 1 MATCH (n)`,
+		`MATCH (n)-[r]-(m RETURN r`,
+		`CALL db.labels()`,
+		`CREATE CONSTRAINT ON (n:MergeNode) ASSERT m.code IS UNIQUE`,
+		`MATCH (n) RETURN 'unterminated`,
+	}
+	for _, cypher := range tests {
+		g := NewGomegaWithT(t)
+		_, err := parse(cypher)
+		g.Expect(err).To(HaveOccurred(), cypher)
+	}
+}
@@ -0,0 +1,319 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//projected is a row projected by a WITH or a RETURN clause. source is the row it's projected from, ORDER BY
+//can refer to its variables unless the projection aggregates or is distinct
+type projected struct {
+	source row
+	values []interface{}
+}
+
+func (c *projectionClause) execute(e *execution, rows []row) ([]row, error) {
+	items, err := c.getItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results     []*projected
+		aggregating = false
+	)
+	for _, item := range items {
+		if call, isCall := item.expression.(*functionCall); isCall && aggregates[call.name] {
+			aggregating = true
+		}
+	}
+	if aggregating {
+		if results, err = aggregate(e, items, rows); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, r := range rows {
+			p := &projected{source: r, values: make([]interface{}, len(items))}
+			for index, item := range items {
+				if p.values[index], err = item.expression.evaluate(e.scope(r)); err != nil {
+					return nil, err
+				}
+			}
+			results = append(results, p)
+		}
+	}
+
+	if c.distinct {
+		var (
+			distinct []*projected
+			seen     = map[string]bool{}
+		)
+		for _, p := range results {
+			if key := valueKey(p.values); !seen[key] {
+				seen[key] = true
+				distinct = append(distinct, p)
+			}
+		}
+		results = distinct
+	}
+
+	projectedRow := func(p *projected) row {
+		r := make(row, len(items))
+		for index, item := range items {
+			r[item.name] = p.values[index]
+		}
+		return r
+	}
+
+	if len(c.orderBy) > 0 {
+		if results, err = c.sort(e, items, results, projectedRow, aggregating || c.distinct); err != nil {
+			return nil, err
+		}
+	}
+	if results, err = c.page(e, results); err != nil {
+		return nil, err
+	}
+
+	var output []row
+	for _, p := range results {
+		r := projectedRow(p)
+		if c.where != nil {
+			kept, err := c.where.evaluate(e.scope(r))
+			if err != nil {
+				return nil, err
+			}
+			if !isTrue(kept) {
+				continue
+			}
+		}
+		output = append(output, r)
+	}
+
+	if c.isReturn {
+		e.columns = make([]string, len(items))
+		for index, item := range items {
+			e.columns[index] = item.name
+		}
+		for _, r := range output {
+			values := make([]interface{}, len(items))
+			for index, item := range items {
+				values[index] = r[item.name]
+			}
+			e.records = append(e.records, values)
+		}
+	}
+	return output, nil
+}
+
+//getItems returns the projected items, with the variables of rows projected by '*'
+func (c *projectionClause) getItems(rows []row) ([]*projectionItem, error) {
+	var items []*projectionItem
+	if c.star && len(rows) > 0 {
+		var names []string
+		for name := range rows[0] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			items = append(items, &projectionItem{&variable{name}, name})
+		}
+	}
+	items = append(items, c.items...)
+
+	names := map[string]bool{}
+	for _, item := range items {
+		if names[item.name] {
+			return nil, errors.New("Multiple result columns with the same name are not supported: " + item.name)
+		}
+		names[item.name] = true
+	}
+	return items, nil
+}
+
+//aggregate groups rows by the values of the items which aren't aggregating, and aggregates the other items
+//over every group. Rows make a single group when every item is aggregating, even when there are no rows
+func aggregate(e *execution, items []*projectionItem, rows []row) ([]*projected, error) {
+	type group struct {
+		projected    *projected
+		aggregations map[int]*aggregation
+	}
+	var (
+		groups []*group
+		byKey  = map[string]*group{}
+	)
+	newGroup := func(source row, values []interface{}) *group {
+		g := &group{&projected{source, values}, map[int]*aggregation{}}
+		for index, item := range items {
+			if call, isCall := item.expression.(*functionCall); isCall && aggregates[call.name] {
+				g.aggregations[index] = newAggregation(call)
+			}
+		}
+		groups = append(groups, g)
+		return g
+	}
+
+	for _, r := range rows {
+		var (
+			values = make([]interface{}, len(items))
+			keys   []string
+			err    error
+		)
+		for index, item := range items {
+			if call, isCall := item.expression.(*functionCall); isCall && aggregates[call.name] {
+				continue
+			}
+			if values[index], err = item.expression.evaluate(e.scope(r)); err != nil {
+				return nil, err
+			}
+			keys = append(keys, valueKey(values[index]))
+		}
+		key := strings.Join(keys, "|")
+		g := byKey[key]
+		if g == nil {
+			g = newGroup(r, values)
+			byKey[key] = g
+		}
+		for _, a := range g.aggregations {
+			if err = a.add(e.scope(r)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		grouped := false
+		for _, item := range items {
+			if call, isCall := item.expression.(*functionCall); !isCall || !aggregates[call.name] {
+				grouped = true
+			}
+		}
+		if !grouped {
+			newGroup(row{}, make([]interface{}, len(items)))
+		}
+	}
+
+	results := make([]*projected, len(groups))
+	for index, g := range groups {
+		for itemIndex, a := range g.aggregations {
+			value, err := a.result()
+			if err != nil {
+				return nil, err
+			}
+			g.projected.values[itemIndex] = value
+		}
+		results[index] = g.projected
+	}
+	return results, nil
+}
+
+//sort sorts results by the ORDER BY items, evaluated on the projected rows. The variables of the source rows
+//are in scope too, unless onlyProjected is true
+func (c *projectionClause) sort(e *execution, items []*projectionItem, results []*projected, projectedRow func(p *projected) row, onlyProjected bool) ([]*projected, error) {
+	keys := make([][]interface{}, len(results))
+	for index, p := range results {
+		r := projectedRow(p)
+		if !onlyProjected {
+			r = p.source.with(r)
+		}
+		keys[index] = make([]interface{}, len(c.orderBy))
+		for keyIndex, item := range c.orderBy {
+			//ORDER BY can repeat a projected expression, which sorts by its projected value
+			if projectedIndex := indexOfExpression(items, item.expression); projectedIndex >= 0 {
+				keys[index][keyIndex] = p.values[projectedIndex]
+				continue
+			}
+			value, err := item.expression.evaluate(e.scope(r))
+			if err != nil {
+				return nil, err
+			}
+			keys[index][keyIndex] = value
+		}
+	}
+
+	positions := make([]int, len(results))
+	for index := range positions {
+		positions[index] = index
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		for keyIndex, item := range c.orderBy {
+			comparison := order(keys[positions[i]][keyIndex], keys[positions[j]][keyIndex])
+			if item.descending {
+				comparison = -comparison
+			}
+			if comparison != 0 {
+				return comparison < 0
+			}
+		}
+		return false
+	})
+
+	sorted := make([]*projected, len(results))
+	for index, position := range positions {
+		sorted[index] = results[position]
+	}
+	return sorted, nil
+}
+
+//indexOfExpression returns the index of the item projecting expression, or -1
+func indexOfExpression(items []*projectionItem, expression expression) int {
+	for index, item := range items {
+		if reflect.DeepEqual(item.expression, expression) {
+			return index
+		}
+	}
+	return -1
+}
+
+//page applies SKIP and LIMIT to results
+func (c *projectionClause) page(e *execution, results []*projected) ([]*projected, error) {
+	for _, bound := range []struct {
+		expression expression
+		name       string
+		skip       bool
+	}{{c.skip, "SKIP", true}, {c.limit, "LIMIT", false}} {
+		if bound.expression == nil {
+			continue
+		}
+		value, err := bound.expression.evaluate(e.scope(row{}))
+		if err != nil {
+			return nil, err
+		}
+		count, isInteger := value.(int64)
+		if !isInteger || count < 0 {
+			return nil, errors.New("Invalid input for " + bound.name + ". Expected a non-negative integer, but was " + typeName(value))
+		}
+		switch {
+		case bound.skip && count >= int64(len(results)):
+			results = nil
+		case bound.skip:
+			results = results[count:]
+		case count < int64(len(results)):
+			results = results[:count]
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,323 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type nodeValue struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+func (n *nodeValue) Id() int64 {
+	return n.id
+}
+
+func (n *nodeValue) Labels() []string {
+	return n.labels
+}
+
+func (n *nodeValue) Props() map[string]interface{} {
+	return n.props
+}
+
+type relationshipValue struct {
+	id      int64
+	startID int64
+	endID   int64
+	relType string
+	props   map[string]interface{}
+}
+
+func (r *relationshipValue) Id() int64 {
+	return r.id
+}
+
+func (r *relationshipValue) StartId() int64 {
+	return r.startID
+}
+
+func (r *relationshipValue) EndId() int64 {
+	return r.endID
+}
+
+func (r *relationshipValue) Type() string {
+	return r.relType
+}
+
+func (r *relationshipValue) Props() map[string]interface{} {
+	return r.props
+}
+
+type pathValue struct {
+	nodes         []neo4j.Node
+	relationships []neo4j.Relationship
+}
+
+func (p *pathValue) Nodes() []neo4j.Node {
+	return p.nodes
+}
+
+func (p *pathValue) Relationships() []neo4j.Relationship {
+	return p.relationships
+}
+
+//toDriverValue converts value to the value the driver returns. Every returned entity has its own copy of
+//properties, like entities decoded by the driver
+func toDriverValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *node:
+		return &nodeValue{v.id, append([]string{}, v.labels...), toDriverValue(v.props).(map[string]interface{})}
+	case *relationship:
+		return &relationshipValue{v.id, v.start, v.end, v.relType, toDriverValue(v.props).(map[string]interface{})}
+	case *path:
+		p := &pathValue{}
+		for _, n := range v.nodes {
+			p.nodes = append(p.nodes, toDriverValue(n).(neo4j.Node))
+		}
+		for _, r := range v.relationships {
+			p.relationships = append(p.relationships, toDriverValue(r).(neo4j.Relationship))
+		}
+		return p
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for index, item := range v {
+			list[index] = toDriverValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = toDriverValue(item)
+		}
+		return m
+	case []byte:
+		return append([]byte{}, v...)
+	}
+	return value
+}
+
+type record struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *record) Keys() []string {
+	return r.keys
+}
+
+func (r *record) Values() []interface{} {
+	return r.values
+}
+
+func (r *record) Get(key string) (interface{}, bool) {
+	for index, k := range r.keys {
+		if k == key {
+			return r.values[index], true
+		}
+	}
+	return nil, false
+}
+
+func (r *record) GetByIndex(index int) interface{} {
+	if index < 0 || index >= len(r.values) {
+		return nil
+	}
+	return r.values[index]
+}
+
+//result holds all the records of a statement, which are computed when the statement is run
+type result struct {
+	keys     []string
+	records  []neo4j.Record
+	current  neo4j.Record
+	position int
+	summary  *summary
+}
+
+func (r *result) Keys() ([]string, error) {
+	return r.keys, nil
+}
+
+func (r *result) Next() bool {
+	if r.position >= len(r.records) {
+		r.current = nil
+		return false
+	}
+	r.current = r.records[r.position]
+	r.position++
+	return true
+}
+
+func (r *result) Err() error {
+	return nil
+}
+
+func (r *result) Record() neo4j.Record {
+	return r.current
+}
+
+func (r *result) Summary() (neo4j.ResultSummary, error) {
+	return r.summary, nil
+}
+
+func (r *result) Consume() (neo4j.ResultSummary, error) {
+	r.position = len(r.records)
+	r.current = nil
+	return r.summary, nil
+}
+
+type summary struct {
+	statement      *statementValue
+	statementType  neo4j.StatementType
+	counters       *counters
+	availableAfter time.Duration
+}
+
+func (s *summary) Server() neo4j.ServerInfo {
+	return serverInfo{}
+}
+
+func (s *summary) Statement() neo4j.Statement {
+	return s.statement
+}
+
+func (s *summary) StatementType() neo4j.StatementType {
+	return s.statementType
+}
+
+func (s *summary) Counters() neo4j.Counters {
+	return s.counters
+}
+
+func (s *summary) Plan() neo4j.Plan {
+	return nil
+}
+
+func (s *summary) Profile() neo4j.ProfiledPlan {
+	return nil
+}
+
+func (s *summary) Notifications() []neo4j.Notification {
+	return nil
+}
+
+func (s *summary) ResultAvailableAfter() time.Duration {
+	return s.availableAfter
+}
+
+func (s *summary) ResultConsumedAfter() time.Duration {
+	return 0
+}
+
+type serverInfo struct{}
+
+func (serverInfo) Address() string {
+	return "inmemory"
+}
+
+func (serverInfo) Version() string {
+	return "inmemory"
+}
+
+type statementValue struct {
+	text   string
+	params map[string]interface{}
+}
+
+func (s *statementValue) Text() string {
+	return s.text
+}
+
+func (s *statementValue) Params() map[string]interface{} {
+	return s.params
+}
+
+type counters struct {
+	nodesCreated         int
+	nodesDeleted         int
+	relationshipsCreated int
+	relationshipsDeleted int
+	propertiesSet        int
+	labelsAdded          int
+	labelsRemoved        int
+	indexesAdded         int
+	indexesRemoved       int
+	constraintsAdded     int
+	constraintsRemoved   int
+}
+
+func (c *counters) ContainsUpdates() bool {
+	return c.nodesCreated > 0 || c.nodesDeleted > 0 || c.relationshipsCreated > 0 || c.relationshipsDeleted > 0 ||
+		c.propertiesSet > 0 || c.labelsAdded > 0 || c.labelsRemoved > 0 || c.indexesAdded > 0 || c.indexesRemoved > 0 ||
+		c.constraintsAdded > 0 || c.constraintsRemoved > 0
+}
+
+func (c *counters) NodesCreated() int {
+	return c.nodesCreated
+}
+
+func (c *counters) NodesDeleted() int {
+	return c.nodesDeleted
+}
+
+func (c *counters) RelationshipsCreated() int {
+	return c.relationshipsCreated
+}
+
+func (c *counters) RelationshipsDeleted() int {
+	return c.relationshipsDeleted
+}
+
+func (c *counters) PropertiesSet() int {
+	return c.propertiesSet
+}
+
+func (c *counters) LabelsAdded() int {
+	return c.labelsAdded
+}
+
+func (c *counters) LabelsRemoved() int {
+	return c.labelsRemoved
+}
+
+func (c *counters) IndexesAdded() int {
+	return c.indexesAdded
+}
+
+func (c *counters) IndexesRemoved() int {
+	return c.indexesRemoved
+}
+
+func (c *counters) ConstraintsAdded() int {
+	return c.constraintsAdded
+}
+
+func (c *counters) ConstraintsRemoved() int {
+	return c.constraintsRemoved
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type session struct {
+	backend      *Backend
+	accessMode   neo4j.AccessMode
+	lastBookmark string
+	transaction  *transaction
+	closed       bool
+}
+
+func (s *session) LastBookmark() string {
+	return s.lastBookmark
+}
+
+func (s *session) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	return s.beginTransaction()
+}
+
+func (s *session) beginTransaction() (*transaction, error) {
+	if s.closed {
+		return nil, errors.New("Session is closed")
+	}
+	if s.transaction != nil {
+		return nil, errors.New("An open transaction already exists in the session")
+	}
+	s.transaction = newTransaction(s)
+	return s.transaction, nil
+}
+
+func (s *session) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(work)
+}
+
+func (s *session) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(work)
+}
+
+//runTransaction runs work in a transaction, committed when work succeeds. Transactions aren't retried, they don't
+//fail with transient errors
+func (s *session) runTransaction(work neo4j.TransactionWork) (interface{}, error) {
+	t, err := s.beginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	value, err := work(t)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.Commit(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *session) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	var result neo4j.Result
+	if _, err := s.runTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		var err error
+		result, err = tx.Run(cypher, params)
+		return result, err
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *session) Close() error {
+	if s.transaction != nil {
+		if err := s.transaction.Rollback(); err != nil {
+			return err
+		}
+	}
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//transaction changes a copy of the graph of the backend. The nodes and relationships it changes are applied to the
+//backend on commit
+type transaction struct {
+	session            *session
+	graph              *graph
+	dirtyNodes         map[int64]bool
+	dirtyRelationships map[int64]bool
+
+	//err is the error of the statement that failed in the transaction. The transaction can only be rolled back
+	err  error
+	done bool
+}
+
+func newTransaction(s *session) *transaction {
+	return &transaction{
+		session:            s,
+		graph:              s.backend.snapshot(),
+		dirtyNodes:         map[int64]bool{},
+		dirtyRelationships: map[int64]bool{}}
+}
+
+func (t *transaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	if t.done {
+		return nil, errors.New("Transaction is closed")
+	}
+	if t.err != nil {
+		return nil, errors.New("Transaction can't run statements after a failed statement. " + t.err.Error())
+	}
+
+	begin := time.Now()
+	r, err := t.run(cypher, params)
+	if err != nil {
+		t.err = err
+		return nil, err
+	}
+	r.summary.availableAfter = time.Since(begin)
+	return r, nil
+}
+
+func (t *transaction) run(cypher string, params map[string]interface{}) (*result, error) {
+	s, err := t.session.backend.parse(cypher)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := map[string]interface{}{}
+	for name, value := range params {
+		if parameters[name], err = normalize(value); err != nil {
+			return nil, err
+		}
+	}
+	summary := &summary{statement: &statementValue{cypher, params}}
+
+	if s.clauses == nil {
+		if summary.counters, err = t.session.backend.runSchemaCommand(s.schema); err != nil {
+			return nil, err
+		}
+		summary.statementType = neo4j.StatementTypeSchemaWrite
+		return &result{summary: summary}, nil
+	}
+
+	e := newExecution(t, parameters)
+	if err = e.run(s); err != nil {
+		return nil, err
+	}
+	if e.counters.ContainsUpdates() {
+		if err = t.session.backend.checkConstraints(t.graph); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &result{keys: e.columns, summary: summary}
+	for _, values := range e.records {
+		for index := range values {
+			values[index] = toDriverValue(values[index])
+		}
+		r.records = append(r.records, &record{e.columns, values})
+	}
+	summary.counters = e.counters
+	summary.statementType = neo4j.StatementTypeReadOnly
+	if e.updating {
+		summary.statementType = neo4j.StatementTypeWriteOnly
+		if e.columns != nil {
+			summary.statementType = neo4j.StatementTypeReadWrite
+		}
+	}
+	return r, nil
+}
+
+func (t *transaction) Commit() error {
+	if t.done {
+		return errors.New("Transaction is closed")
+	}
+	if t.err != nil {
+		t.end()
+		return errors.New("Transaction was rolled back. " + t.err.Error())
+	}
+	bookmark, err := t.session.backend.commit(t)
+	t.end()
+	if err != nil {
+		return err
+	}
+	t.session.lastBookmark = bookmark
+	return nil
+}
+
+func (t *transaction) Rollback() error {
+	t.end()
+	return nil
+}
+
+func (t *transaction) Close() error {
+	if !t.done {
+		return t.Rollback()
+	}
+	return nil
+}
+
+func (t *transaction) end() {
+	t.done = true
+	if t.session.transaction == t {
+		t.session.transaction = nil
+	}
+}
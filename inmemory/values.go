@@ -0,0 +1,423 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inmemory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//normalize converts a parameter to the values the driver sends to the database: integers to int64, floats to
+//float64, slices to lists and maps to maps of strings. Pointers are dereferenced
+func normalize(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time, neo4j.Date, neo4j.LocalTime, neo4j.OffsetTime, neo4j.LocalDateTime, neo4j.Duration, *neo4j.Point:
+		return v, nil
+	case *node, *relationship, *path:
+		return v, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return normalize(v.Elem().Interface())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte{}, v.Slice(0, v.Len()).Bytes()...), nil
+		}
+		list := make([]interface{}, v.Len())
+		for index := range list {
+			item, err := normalize(v.Index(index).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list[index] = item
+		}
+		return list, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, errors.New("Unable to convert a map with keys of type " + v.Type().Key().String())
+		}
+		m := make(map[string]interface{}, v.Len())
+		iterator := v.MapRange()
+		for iterator.Next() {
+			item, err := normalize(iterator.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[iterator.Key().String()] = item
+		}
+		return m, nil
+	}
+	return nil, errors.New("Unable to convert a value of type " + v.Type().String())
+}
+
+//checkPropertyValue returns an error when value can't be stored as a property
+func checkPropertyValue(key string, value interface{}) error {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			switch item.(type) {
+			case []interface{}, map[string]interface{}, *node, *relationship, *path, nil:
+				return errors.New("Property values can only be of primitive types or arrays thereof. Property '" + key + "' isn't")
+			}
+		}
+	case map[string]interface{}, *node, *relationship, *path:
+		return errors.New("Property values can only be of primitive types or arrays thereof. Property '" + key + "' isn't")
+	}
+	return nil
+}
+
+func asFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func asInteger(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+//equals is the Cypher equality of a and b. It's null when any of a or b is null
+func equals(a interface{}, b interface{}) interface{} {
+	if a == nil || b == nil {
+		return nil
+	}
+	switch x := a.(type) {
+	case int64, float64:
+		if _, isNumber := asFloat(b); !isNumber {
+			return false
+		}
+		if i, isInteger := x.(int64); isInteger {
+			if j, isInteger := b.(int64); isInteger {
+				return i == j
+			}
+		}
+		fx, _ := asFloat(x)
+		fy, _ := asFloat(b)
+		return fx == fy
+	case string:
+		y, isString := b.(string)
+		return isString && x == y
+	case bool:
+		y, isBool := b.(bool)
+		return isBool && x == y
+	case []interface{}:
+		y, isList := b.([]interface{})
+		if !isList || len(x) != len(y) {
+			return false
+		}
+		var result interface{} = true
+		for index := range x {
+			switch equals(x[index], y[index]) {
+			case false:
+				return false
+			case nil:
+				result = nil
+			}
+		}
+		return result
+	case map[string]interface{}:
+		y, isMap := b.(map[string]interface{})
+		if !isMap || len(x) != len(y) {
+			return false
+		}
+		var result interface{} = true
+		for key, value := range x {
+			other, exists := y[key]
+			if !exists {
+				return false
+			}
+			switch equals(value, other) {
+			case false:
+				return false
+			case nil:
+				result = nil
+			}
+		}
+		return result
+	case *node:
+		y, isNode := b.(*node)
+		return isNode && x.id == y.id
+	case *relationship:
+		y, isRelationship := b.(*relationship)
+		return isRelationship && x.id == y.id
+	case *path:
+		y, isPath := b.(*path)
+		return isPath && valueKey(x) == valueKey(y)
+	case time.Time:
+		y, isTime := b.(time.Time)
+		return isTime && x.Equal(y)
+	case []byte:
+		y, isBytes := b.([]byte)
+		return isBytes && bytes.Equal(x, y)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+//compare compares a and b for the inequality operators. It returns false when they aren't comparable
+func compare(a interface{}, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case int64, float64:
+		if i, isInteger := x.(int64); isInteger {
+			if j, isInteger := b.(int64); isInteger {
+				return compareInt64(i, j), true
+			}
+		}
+		fx, _ := asFloat(x)
+		fy, isNumber := asFloat(b)
+		if !isNumber || math.IsNaN(fx) || math.IsNaN(fy) {
+			return 0, false
+		}
+		return compareFloat64(fx, fy), true
+	case string:
+		if y, isString := b.(string); isString {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, isBool := b.(bool); isBool {
+			return compareInt64(boolAsInt64(x), boolAsInt64(y)), true
+		}
+	case time.Time:
+		if y, isTime := b.(time.Time); isTime {
+			switch {
+			case x.Before(y):
+				return -1, true
+			case x.After(y):
+				return 1, true
+			}
+			return 0, true
+		}
+	case []interface{}:
+		if y, isList := b.([]interface{}); isList {
+			for index := 0; index < len(x) && index < len(y); index++ {
+				c, comparable := compare(x[index], y[index])
+				if !comparable {
+					return 0, false
+				}
+				if c != 0 {
+					return c, true
+				}
+			}
+			return compareInt64(int64(len(x)), int64(len(y))), true
+		}
+	}
+	return 0, false
+}
+
+func compareInt64(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolAsInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+//orderRank is the rank of the type of value in the ascending order of ORDER BY. Null is sorted last
+func orderRank(value interface{}) int {
+	switch value.(type) {
+	case map[string]interface{}:
+		return 0
+	case *node:
+		return 1
+	case *relationship:
+		return 2
+	case []interface{}:
+		return 3
+	case *path:
+		return 4
+	case string:
+		return 5
+	case bool:
+		return 6
+	case int64, float64:
+		return 7
+	case nil:
+		return 9
+	}
+	return 8
+}
+
+//order compares a and b for ORDER BY, in which values of all types are ordered
+func order(a interface{}, b interface{}) int {
+	rankA, rankB := orderRank(a), orderRank(b)
+	if rankA != rankB {
+		return compareInt64(int64(rankA), int64(rankB))
+	}
+	switch x := a.(type) {
+	case *node:
+		return compareInt64(x.id, b.(*node).id)
+	case *relationship:
+		return compareInt64(x.id, b.(*relationship).id)
+	case []interface{}:
+		y := b.([]interface{})
+		for index := 0; index < len(x) && index < len(y); index++ {
+			if c := order(x[index], y[index]); c != 0 {
+				return c
+			}
+		}
+		return compareInt64(int64(len(x)), int64(len(y)))
+	}
+	if c, comparable := compare(a, b); comparable {
+		return c
+	}
+	return strings.Compare(valueKey(a), valueKey(b))
+}
+
+//valueKey returns a key of value. Equal values have the same key, for DISTINCT and grouping
+func valueKey(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return "n:" + strconv.FormatInt(v, 10)
+	case float64:
+		if i, isInteger := asInteger(v); isInteger {
+			return "n:" + strconv.FormatInt(i, 10)
+		}
+		return "f:" + strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return "s:" + strconv.Quote(v)
+	case bool:
+		return "b:" + strconv.FormatBool(v)
+	case *node:
+		return "node:" + strconv.FormatInt(v.id, 10)
+	case *relationship:
+		return "relationship:" + strconv.FormatInt(v.id, 10)
+	case *path:
+		keys := make([]string, 0, len(v.nodes)+len(v.relationships))
+		for index, n := range v.nodes {
+			keys = append(keys, valueKey(n))
+			if index < len(v.relationships) {
+				keys = append(keys, valueKey(v.relationships[index]))
+			}
+		}
+		return "path:" + strings.Join(keys, ",")
+	case []interface{}:
+		keys := make([]string, len(v))
+		for index, item := range v {
+			keys[index] = valueKey(item)
+		}
+		return "[" + strings.Join(keys, ",") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for index, key := range keys {
+			keys[index] = strconv.Quote(key) + ":" + valueKey(v[key])
+		}
+		return "{" + strings.Join(keys, ",") + "}"
+	case time.Time:
+		return "t:" + v.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%T:%#v", value, value)
+}
+
+//isTrue tells whether value is the boolean true. Rows are only kept by predicates evaluated to true
+func isTrue(value interface{}) bool {
+	b, isBool := value.(bool)
+	return isBool && b
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "Null"
+	case int64:
+		return "Integer"
+	case float64:
+		return "Float"
+	case string:
+		return "String"
+	case bool:
+		return "Boolean"
+	case []interface{}:
+		return "List"
+	case map[string]interface{}:
+		return "Map"
+	case *node:
+		return "Node"
+	case *relationship:
+		return "Relationship"
+	case *path:
+		return "Path"
+	}
+	return reflect.TypeOf(value).String()
+}
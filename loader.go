@@ -23,6 +23,7 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"sort"
@@ -44,7 +45,7 @@ func newLoader(cypherExecuter *cypherExecuter, store store, eventer eventer, reg
 	return &loader{cypherExecuter, store, eventer, registry, graphFactory, allowCyclicRef}
 }
 
-func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptions, reload bool) (store, error) {
+func (l *loader) load(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions, reload bool) (store, error) {
 
 	var (
 		valueOfObject = reflect.ValueOf(object)
@@ -68,7 +69,7 @@ func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptio
 	}
 	dummyValue := reflect.New(elem(reflect.TypeOf(object)).Elem())
 	graphs[0].setValue(&dummyValue)
-	sliceOfObjs, unloadedGraphs, err := l.loadAllOfGraphType(graphs[0], ptrToSliceIDs.Elem().Interface(), loadOptions, reload)
+	sliceOfObjs, unloadedGraphs, err := l.loadAllOfGraphType(ctx, graphs[0], ptrToSliceIDs.Elem().Interface(), loadOptions, reload)
 
 	if err != nil {
 		return nil, err
@@ -83,7 +84,7 @@ func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptio
 	return unloadedGraphs, err
 }
 
-func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
+func (l *loader) loadAll(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
 
 	var (
 		graphs      []graph
@@ -102,7 +103,7 @@ func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *Load
 
 	dummyValue := reflect.New(elem(reflect.TypeOf(objects)).Elem())
 	graphs[0].setValue(&dummyValue)
-	if sliceOfObjs, _, err = l.loadAllOfGraphType(graphs[0], IDs, loadOptions, false); err != nil {
+	if sliceOfObjs, _, err = l.loadAllOfGraphType(ctx, graphs[0], IDs, loadOptions, false); err != nil {
 		return err
 	}
 
@@ -112,12 +113,15 @@ func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *Load
 	return nil
 }
 
-func (l *loader) reload(objects ...interface{}) error {
+func (l *loader) reload(ctx context.Context, objects ...interface{}) error {
 	var err error
 	var graphs []graph
 	var IDer = getIDer(nil, nil)
 	var storedGraph graph
 	for _, object := range objects {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		valueOfObject := reflect.ValueOf(object)
 		//object: **DomainObject
 		if graphs, err = l.graphFactory.get(valueOfObject, map[int]bool{labels: true, relatedGraph: true}); err != nil {
@@ -143,7 +147,7 @@ func (l *loader) reload(objects ...interface{}) error {
 		}
 		storedUnwound := unwind(storedGraph, lo.Depth)
 		var unloadedGraphs store
-		if unloadedGraphs, err = l.load(valueOfObject.Interface(), ID, lo, true); err != nil {
+		if unloadedGraphs, err = l.load(ctx, valueOfObject.Interface(), ID, lo, true); err != nil {
 			return err
 		}
 
@@ -153,10 +157,10 @@ func (l *loader) reload(objects ...interface{}) error {
 				if unloadedUnwound.get(g) == nil {
 					deletedGraphs, updatedGraphs := l.store.delete(g)
 					for _, updatedGraph := range updatedGraphs {
-						notifyPostDelete(l.eventer, updatedGraph, UPDATE)
+						notifyPostDelete(l.eventer, l.cypherExecuter.transaction, updatedGraph, UPDATE)
 					}
 					for _, deletedGraph := range deletedGraphs {
-						notifyPostDelete(l.eventer, deletedGraph, DELETE)
+						notifyPostDelete(l.eventer, l.cypherExecuter.transaction, deletedGraph, DELETE)
 					}
 				}
 			}
@@ -165,7 +169,7 @@ func (l *loader) reload(objects ...interface{}) error {
 	return nil
 }
 
-func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions *LoadOptions, reload bool) (reflect.Value, store, error) {
+func (l *loader) loadAllOfGraphType(ctx context.Context, refGraph graph, IDs interface{}, loadOptions *LoadOptions, reload bool) (reflect.Value, store, error) {
 
 	var (
 		typeOfRefGraph = reflect.TypeOf(refGraph)
@@ -186,6 +190,10 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 	if err != nil {
 		return invalidValue, nil, err
 	}
+
+	if err = ctx.Err(); err != nil {
+		return invalidValue, nil, err
+	}
 	customIDName, _ := metadata.getCustomID(*refGraph.getValue())
 
 	ptrToObjs.Elem().Set(sliceOfPtrToObjs)
@@ -199,7 +207,7 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 		IDsToLoad := reflect.New(sliceOfIDsToLoad.Type())
 		IDsToLoad.Elem().Set(sliceOfIDsToLoad)
 
-		if loadOptions.Depth <= -1 || reload {
+		if loadOptions.Depth <= -1 || loadOptions.isRestricted() || reload {
 			IDsToLoad.Elem().Set(valueOfIDs)
 		} else {
 			for i := 0; i < valueOfIDs.Len(); i++ {
@@ -235,7 +243,12 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 		return invalidValue, nil, err
 	}
 
-	if records, err = neo4j.Collect(l.cypherExecuter.exec(cypherBuilder.getLoadAll(ids, loadOptions))); err != nil {
+	cypher, parameters, err := cypherBuilder.getLoadAll(ids, loadOptions)
+	if err != nil {
+		return invalidValue, nil, err
+	}
+
+	if records, err = neo4j.Collect(l.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
 		return invalidValue, nil, err
 	}
 
@@ -246,9 +259,15 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 	relatedValues[typeOfPrivateNode] = map[int64]map[int64]bool{}
 	relatedValues[typeOfPrivateRelationship] = map[int64]map[int64]bool{}
 
+	//Root graphs in the order of the load result
+	var rootGraphs []graph
 	for _, record := range records {
 		refGraph.setID(record.GetByIndex(1).(int64))
-		toUnLoad.save(l.getGraphToLoadFromDBResult(record.GetByIndex(0).(neo4j.Path), record.GetByIndex(2).([]interface{}), refGraph, visitedGraphs, loadOptions.Depth))
+		rootGraph := l.getGraphToLoadFromDBResult(record.GetByIndex(0).(neo4j.Path), record.GetByIndex(2).([]interface{}), refGraph, visitedGraphs, loadOptions.Depth)
+		if toUnLoad.get(rootGraph) == nil {
+			rootGraphs = append(rootGraphs, rootGraph)
+		}
+		toUnLoad.save(rootGraph)
 	}
 
 	for _, g := range toUnLoad.all() {
@@ -266,29 +285,17 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 
 	for _, g := range unloadedGrahps.all() {
 		g.setCoordinate(nil)
-		isRoot := toUnLoad.get(g) != nil
 		if stored := l.store.get(g); !reload && stored != nil && stored.getDepth() != nil && g.getDepth() != nil && *stored.getDepth() >= *g.getDepth() {
-			if stored.getValue().IsValid() {
-				for _, eventListener := range l.eventer.eventListeners {
-					eventListener.OnPostLoad(event{object: stored.getValue()})
-				}
-			}
-			if isRoot {
-				ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *stored.getValue()))
-			}
+			notifyPostLoad(l.eventer, stored)
 			continue
 		}
 
 		l.store.save(g)
-		if g.getValue().IsValid() {
-			for _, eventListener := range l.eventer.eventListeners {
-				eventListener.OnPostLoad(event{object: g.getValue()})
-			}
-		}
+		notifyPostLoad(l.eventer, g)
+	}
 
-		if isRoot {
-			ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *g.getValue()))
-		}
+	for _, rootGraph := range rootGraphs {
+		ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *l.store.get(rootGraph).getValue()))
 	}
 
 	return ptrToObjs.Elem(), toUnLoad, nil
@@ -33,6 +33,9 @@ type metadata interface {
 	getLabel(reflect.Value) (string, error)
 	getProperties(reflect.Value) map[string]interface{}
 	getCustomID(reflect.Value) (string, reflect.Value)
+	getVersion(reflect.Value) (string, reflect.Value)
+	getSoftDeleteBackendName() string
+	getMergePropertyNames() []string
 	loadRelatedGraphs(g graph, ID func(graph), registry *registry) (map[int64]graph, error)
 	getGraphField(ref graph, relatedGraph graph) (*field, error)
 	getPropertyStructFields() map[string]*reflect.StructField
@@ -46,7 +49,10 @@ type commonMetadata struct {
 	registry             *registry
 	propertyStructFields map[string]*reflect.StructField
 	customIDBackendName  string
-	_type                reflect.Type
+	versionBackendName   string
+	//softDeleteBackendName is the property set on delete instead of deleting entities
+	softDeleteBackendName string
+	_type                 reflect.Type
 }
 
 func (c *commonMetadata) getType() reflect.Type {
@@ -64,6 +70,34 @@ func (c *commonMetadata) getCustomID(v reflect.Value) (string, reflect.Value) {
 	return emptyString, invalidValue
 }
 
+func (c *commonMetadata) getVersion(v reflect.Value) (string, reflect.Value) {
+	if c.versionBackendName != emptyString {
+		return c.versionBackendName, v.Elem().FieldByName(c.propertyStructFields[c.versionBackendName].Name)
+	}
+	return emptyString, invalidValue
+}
+
+func (c *commonMetadata) getSoftDeleteBackendName() string {
+	return c.softDeleteBackendName
+}
+
+//getMergePropertyNames returns the sorted names of the properties matching existing entities on merge.
+//That is the custom ID, or the unique properties when there's no custom ID. Types with several unique properties
+//and no custom ID can't be merged, see validateMerge
+func (c *commonMetadata) getMergePropertyNames() []string {
+	if c.customIDBackendName != emptyString {
+		return []string{c.customIDBackendName}
+	}
+	var names []string
+	for name, structField := range c.propertyStructFields {
+		if len(getNamespacedTag(structField.Tag).get(uniqueTag)) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (c *commonMetadata) getPropertyStructFields() map[string]*reflect.StructField {
 	return c.propertyStructFields
 }
@@ -114,6 +148,14 @@ func getMetadata(t reflect.Type, registry *registry) (metadata, error) {
 	if customIDBackendName, err = getCustomIDBackendName(propertyStructFields); err != nil {
 		return nil, err
 	}
+	var versionBackendName string
+	if versionBackendName, err = getVersionBackendName(propertyStructFields); err != nil {
+		return nil, err
+	}
+	var softDeleteBackendName string
+	if softDeleteBackendName, err = getSoftDeleteBackendName(propertyStructFields); err != nil {
+		return nil, err
+	}
 
 	if typeOfInternalGraph == typeOfPrivateRelationship {
 		r := newRelationshipMetadata()
@@ -122,6 +164,8 @@ func getMetadata(t reflect.Type, registry *registry) (metadata, error) {
 		r.structLabel = getRelationshipType(typeOfObject.Elem())
 		r.propertyStructFields = propertyStructFields
 		r.customIDBackendName = customIDBackendName
+		r.versionBackendName = versionBackendName
+		r.softDeleteBackendName = softDeleteBackendName
 		r._type = typeOfObject
 
 		endpointFields, _ := getFeilds(valueOfObject.Elem(), isRelationshipEndPointFieldFilter(startNodeTag), isRelationshipEndPointFieldFilter(endNodeTag))
@@ -144,12 +188,25 @@ func getMetadata(t reflect.Type, registry *registry) (metadata, error) {
 		r.endpoints[startNode] = endpointFields[startNode][0].getStructField()
 		r.endpoints[endNode] = endpointFields[endNode][0].getStructField()
 
+		for name, structField := range propertyStructFields {
+			if len(getNamespacedTag(structField.Tag).get(mergeTag)) > 0 {
+				r.mergeBackendNames = append(r.mergeBackendNames, name)
+			}
+		}
+		sort.Strings(r.mergeBackendNames)
+		r.merge = len(r.mergeBackendNames) > 0
+		for _, embeddingStructField := range getEmbeddingStructFields(typeOfObject.Elem(), typeOfPublicRelationship) {
+			r.merge = r.merge || len(getNamespacedTag(embeddingStructField.Tag).get(mergeTag)) > 0
+		}
+
 		metadata = r
 	} else {
 		n := newNodeMetadata()
 		n.registry = registry
 		n.name = typeOfObject.String()
 		n.customIDBackendName = customIDBackendName
+		n.versionBackendName = versionBackendName
+		n.softDeleteBackendName = softDeleteBackendName
 		n.thisStructLabel = getThisStructLabels(typeOfObject.Elem())
 		n._type = typeOfObject
 
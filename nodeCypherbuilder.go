@@ -11,6 +11,7 @@ type nodeQueryBuilder struct {
 	isLabelsDirty                  bool
 	removedRelationships           map[int64]graph
 	removedRelationshipsOtherNodes map[int64]graph
+	versionCheck                   *versionCheck
 }
 
 func (nqb nodeQueryBuilder) getGraph() graph {
@@ -70,14 +71,15 @@ func (nqb nodeQueryBuilder) diffNodeRelatedGraphs(ref graph) (addedGraphs map[in
 	return removedRelationships, removedRelationshipsOtherNodes, nil
 }
 
-func newNodeCypherBuilder(n *node, registry *registry, stored graph) (*nodeQueryBuilder, error) {
+func newNodeCypherBuilder(n *node, registry *registry, stored graph, versionCheck *versionCheck) (*nodeQueryBuilder, error) {
 
 	var err error
 	nqb := &nodeQueryBuilder{
 		n:               n,
 		registry:        registry,
 		deltaProperties: n.getProperties(),
-		isLabelsDirty:   true}
+		isLabelsDirty:   true,
+		versionCheck:    versionCheck}
 
 	if stored != nil {
 		nqb.deltaProperties = diffProperties(n.getProperties(), stored.getProperties())
@@ -99,12 +101,40 @@ func (nqb nodeQueryBuilder) isGraphDirty() bool {
 	return nqb.n.getID() < 0 || len(nqb.deltaProperties) > 0 || len(nqb.removedRelationships) > 0 || nqb.isLabelsDirty
 }
 
-func (nqb nodeQueryBuilder) getCreate() (string, string, map[string]interface{}, map[string]graph) {
+func (nqb nodeQueryBuilder) getCreate(saveOptions *SaveOptions) (string, string, map[string]interface{}, map[string]graph) {
+	if saveOptions.Merge {
+		metadata, _ := nqb.registry.get(nqb.n.getValue().Type())
+		if pattern, parameters := getMergePattern(nqb.n, metadata.getMergePropertyNames()); pattern != emptyString {
+			merge := `MERGE (` + nqb.n.getSignature() + `:` + nqb.n.getLabel() + pattern + `)
+	`
+			return merge, emptyString, parameters, nil
+		}
+	}
 	create := `CREATE (` + nqb.n.getSignature() + `)
 	`
 	return create, emptyString, nil, nil
 }
 
+//getMergeMatch returns the clause matching the entity a new node is merged on, to check its version and tell whether
+//the merge created the node. It's empty for nodes that aren't merged
+func (nqb nodeQueryBuilder) getMergeMatch(saveOptions *SaveOptions) (string, map[string]interface{}) {
+	if !saveOptions.Merge || nqb.n.getID() >= 0 {
+		return emptyString, nil
+	}
+	metadata, _ := nqb.registry.get(nqb.n.getValue().Type())
+	pattern, parameters := getMergePattern(nqb.n, metadata.getMergePropertyNames())
+	if pattern == emptyString {
+		return emptyString, nil
+	}
+	matchedRef := nqb.n.getSignature() + "Matched"
+	match := `OPTIONAL MATCH (` + matchedRef + `:` + nqb.n.getLabel() + pattern + `)
+	`
+	if nqb.versionCheck != nil {
+		match += getMergeVersionCheck(matchedRef, nqb.n.getSignature(), nqb.versionCheck, parameters)
+	}
+	return match, parameters
+}
+
 func (nqb nodeQueryBuilder) getMatch() (string, map[string]interface{}, map[string]graph) {
 	var (
 		nSign                                       = nqb.n.getSignature()
@@ -122,23 +152,41 @@ func (nqb nodeQueryBuilder) getMatch() (string, map[string]interface{}, map[stri
 		`
 		parameters[idCQLRef] = customIDPropertyValue.Interface()
 	}
+	if nqb.versionCheck != nil && nqb.n.getID() >= 0 && nqb.isGraphDirty() {
+		versionCQLRef := nSign + "Version"
+		filter += `AND coalesce(` + nSign + `.` + nqb.versionCheck.propertyName + `, 0) = $` + versionCQLRef + `
+	`
+		parameters[versionCQLRef] = nqb.versionCheck.version
+	}
 
 	return match + filter, parameters, nil
 }
+
+//getSetProperties returns the properties set on save, with the next version of versioned nodes
+func (nqb nodeQueryBuilder) getSetProperties() map[string]interface{} {
+	properties := map[string]interface{}{}
+	for propertyName, propertyValue := range nqb.deltaProperties {
+		if !metaProperties[propertyName] {
+			properties[propertyName] = propertyValue
+		}
+	}
+	if nqb.versionCheck != nil {
+		version := nqb.versionCheck.next()
+		nqb.n.getProperties()[nqb.versionCheck.propertyName] = version
+		properties[nqb.versionCheck.propertyName] = version
+	}
+	return properties
+}
+
 func (nqb nodeQueryBuilder) getSet() (string, map[string]interface{}) {
 
 	var (
 		set        string
 		nSign      = nqb.n.getSignature()
-		properties = map[string]interface{}{}
+		properties = nqb.getSetProperties()
 		parameters = map[string]interface{}{}
 		propCQLRef = nSign + "Properties"
 	)
-	for propertyName, propertyValue := range nqb.deltaProperties {
-		if !metaProperties[propertyName] {
-			properties[propertyName] = propertyValue
-		}
-	}
 
 	if len(properties) > 0 {
 		set += `SET ` + nSign + ` += $` + propCQLRef + `
@@ -154,37 +202,64 @@ func (nqb nodeQueryBuilder) getSet() (string, map[string]interface{}) {
 	return set, parameters
 }
 
-func (nqb nodeQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}) {
+func (nqb nodeQueryBuilder) getUnwindCreate(saveOptions *SaveOptions) (string, map[string]interface{}) {
+	var (
+		create  = `CREATE (n:` + nqb.n.getLabel() + `)`
+		row     = map[string]interface{}{"ref": nqb.n.getSignature(), "properties": nqb.getSetProperties()}
+		_return = `RETURN row.ref, ID(n)`
+	)
+	if saveOptions.Merge {
+		metadata, _ := nqb.registry.get(nqb.n.getValue().Type())
+		if properties := getMergeProperties(nqb.n, metadata.getMergePropertyNames()); properties != nil {
+			pattern := getUnwindMergePattern(metadata.getMergePropertyNames())
+			create = `OPTIONAL MATCH (matched:` + nqb.n.getLabel() + pattern + `)
+	`
+			if nqb.versionCheck != nil {
+				create += getUnwindMergeVersionCheck("row, matched", nqb.versionCheck)
+				row["version"] = nqb.versionCheck.version
+			}
+			create += `MERGE (n:` + nqb.n.getLabel() + pattern + `)`
+			row["merge"] = properties
+			_return += `, matched IS NULL`
+		}
+	}
+	return `UNWIND $rows AS row
+	` + create + `
+	SET n += row.properties
+	` + _return, row
+}
+
+func (nqb nodeQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error) {
 
 	var (
 		depth                   = strconv.Itoa(lo.Depth)
 		metadata, _             = nqb.registry.get(nqb.n.getValue().Type())
 		customIDPropertyName, _ = metadata.getCustomID(*nqb.n.getValue())
-		parameters              = map[string]interface{}{}
 	)
 	if lo.Depth == infiniteDepth {
 		depth = emptyString
 	}
 
-	match := `MATCH path = (n:` + nqb.n.getLabel() + `)-[*0..` + depth + `]-()
-	`
-	var filter string
-	if IDs != nil {
-		filter = `WHERE ID(n) IN $ids 
-		`
-		if customIDPropertyName != emptyString {
-			filter = `WHERE n.` + customIDPropertyName + ` IN $ids 
-			`
-		}
-		parameters["ids"] = IDs
+	filter, page, orderBy, parameters, err := getLoadAllRootClauses("n", IDs, customIDPropertyName, lo, metadata, nqb.registry)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	pathFilter, err := getLoadAllPathFilter(lo, metadata, nqb.registry)
+	if err != nil {
+		return emptyString, nil, err
 	}
 
+	match := `MATCH (n:` + nqb.n.getLabel() + `)
+	`
+	expand := `MATCH path = (n)-[*0..` + depth + `]-()
+	`
+
 	end := `WITH n, path, range(0, length(path) - 1) as index
 	WITH  n, path, index, [i in index | CASE WHEN nodes(path)[i] = startNode(relationships(path)[i]) THEN false ELSE true END] as isDirectionInverted
 	RETURN path, ID(n), isDirectionInverted
-	`
+	` + orderBy
 
-	return match + filter + end, parameters
+	return match + filter + page + expand + pathFilter + end, parameters, nil
 }
 
 func (nqb nodeQueryBuilder) getDelete() (string, map[string]interface{}, map[string]graph) {
@@ -198,6 +273,31 @@ func (nqb nodeQueryBuilder) getDeleteAll() (string, map[string]interface{}) {
 	return `MATCH (n:` + nqb.n.getLabel() + `) DETACH DELETE n RETURN ID(n)`, nil
 }
 
+func (nqb nodeQueryBuilder) getSoftDelete(softDeleteValue interface{}) (string, map[string]interface{}, map[string]graph) {
+	var (
+		nSign                = nqb.n.getSignature()
+		metadata, _          = nqb.registry.get(nqb.n.getValue().Type())
+		softDeleteCQLRef     = nSign + "SoftDelete"
+		match, parameters, _ = nqb.getMatch()
+	)
+	parameters[softDeleteCQLRef] = softDeleteValue
+	softDelete := `SET ` + nSign + `.` + metadata.getSoftDeleteBackendName() + ` = $` + softDeleteCQLRef + ` RETURN ID(` + nSign + `)
+	`
+	return match + softDelete, parameters, nil
+}
+
+func (nqb nodeQueryBuilder) getSoftDeleteAll(softDeleteValue interface{}) (string, map[string]interface{}) {
+	metadata, _ := nqb.registry.get(nqb.n.getValue().Type())
+	return `MATCH (n:` + nqb.n.getLabel() + `) WHERE ` + getNotSoftDeletedPredicate("n", metadata) + ` SET n.` + metadata.getSoftDeleteBackendName() + ` = $softDelete RETURN ID(n)`, map[string]interface{}{"softDelete": softDeleteValue}
+}
+
 func (nqb nodeQueryBuilder) getCountEntitiesOfType() (string, map[string]interface{}) {
-	return `MATCH (n:` + nqb.n.getLabel() + `) RETURN count(n) as count`, nil
+	var (
+		metadata, _ = nqb.registry.get(nqb.n.getValue().Type())
+		filter      string
+	)
+	if predicate := getNotSoftDeletedPredicate("n", metadata); predicate != emptyString {
+		filter = ` WHERE ` + predicate
+	}
+	return `MATCH (n:` + nqb.n.getLabel() + `)` + filter + ` RETURN count(n) as count`, nil
 }
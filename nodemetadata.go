@@ -233,6 +233,7 @@ func extractRelationshipTypeA(n *node, ID func(graph), registry *registry) func(
 					relType:    relType,
 					properties: make(map[string]interface{})}
 			}
+			relationshipA.merge = len(f.tag.get(mergeTag)) > 0
 			ID(relationshipA.nodes[startNode])
 			ID(relationshipA.nodes[endNode])
 			ID(relationshipA)
@@ -258,7 +259,7 @@ func extractRelationshipTypeB(n *node, ID func(graph), registry *registry) func(
 		values := getEntitiesFromField(f)
 		for i := 0; i < len(values); i++ {
 			var label string
-			relationship := &relationship{ID: initialGraphID, Value: &values[i]}
+			relationship := &relationship{ID: initialGraphID, Value: &values[i], merge: len(f.tag.get(mergeTag)) > 0}
 			if relationship.nodes, err = metadata.loadRelatedGraphs(relationship, ID, registry); err != nil {
 				return nil, err
 			}
@@ -273,3 +274,117 @@ func extractRelationshipTypeB(n *node, ID func(graph), registry *registry) func(
 		return relationships, nil
 	}
 }
+
+//getRelatedPattern returns a pattern comprehension of the entities related to the node ref through the field fieldName
+func (nm *nodeMetadata) getRelatedPattern(ref string, relatedRef string, fieldName string) (string, metadata, error) {
+	var (
+		relatedMetadata metadata
+		err             error
+	)
+	for _, relationshipAStructField := range nm.relationshipAStructFields {
+		if relationshipAStructField.Name != fieldName {
+			continue
+		}
+		if relatedMetadata, err = nm.registry.get(elem(relationshipAStructField.Type)); err != nil {
+			return emptyString, nil, err
+		}
+		f := &field{
+			parent: reflect.New(nm._type.Elem()).Elem(),
+			name:   relationshipAStructField.Name,
+			tag:    getNamespacedTag(relationshipAStructField.Tag)}
+
+		relationship := `-[:` + f.getRelType() + `]-`
+		switch f.getEffectiveDirection() {
+		case outgoing:
+			relationship += `>`
+		case incoming:
+			relationship = `<` + relationship
+		}
+		return `[(` + ref + `)` + relationship + `(` + relatedRef + `:` + relatedMetadata.getStructLabel() + `) | ` + relatedRef + `]`, relatedMetadata, nil
+	}
+
+	for _, relationshipBStructField := range nm.relationshipBStructFields {
+		if relationshipBStructField.Name != fieldName {
+			continue
+		}
+		if relatedMetadata, err = nm.registry.get(elem(relationshipBStructField.Type)); err != nil {
+			return emptyString, nil, err
+		}
+		rMetadata := relatedMetadata.(*relationshipMetadata)
+		fromNodeType := rMetadata.endpoints[startNode].Type
+		toNodeType := rMetadata.endpoints[endNode].Type
+
+		relationship := `-[` + relatedRef + `:` + rMetadata.getStructLabel() + `]-`
+		if fromNodeType == nm._type && toNodeType != nm._type {
+			relationship += `>`
+		} else if toNodeType == nm._type && fromNodeType != nm._type {
+			relationship = `<` + relationship
+		}
+		return `[(` + ref + `)` + relationship + `() | ` + relatedRef + `]`, relatedMetadata, nil
+	}
+
+	return emptyString, nil, nil
+}
+
+//getCascadeRelationships returns the relationship patterns to the nodes related through the relationship fields tagged
+//'cascade:delete', with the metadata of the related nodes
+func (nm *nodeMetadata) getCascadeRelationships() ([]string, []metadata, error) {
+	var (
+		relationships    []string
+		relatedMetadatas []metadata
+	)
+	for _, relationshipAStructField := range nm.relationshipAStructFields {
+		tag := getNamespacedTag(relationshipAStructField.Tag)
+		if indexOfString(tag.get(cascadeTag), cascadeDelete) < 0 {
+			continue
+		}
+		relatedMetadata, err := nm.registry.get(elem(relationshipAStructField.Type))
+		if err != nil {
+			return nil, nil, err
+		}
+		f := &field{
+			parent: reflect.New(nm._type.Elem()).Elem(),
+			name:   relationshipAStructField.Name,
+			tag:    tag}
+
+		relationship := `-[:` + f.getRelType() + `]-`
+		switch f.getEffectiveDirection() {
+		case outgoing:
+			relationship += `>`
+		case incoming:
+			relationship = `<` + relationship
+		}
+		relationships = append(relationships, relationship)
+		relatedMetadatas = append(relatedMetadatas, relatedMetadata)
+	}
+
+	for _, relationshipBStructField := range nm.relationshipBStructFields {
+		if indexOfString(getNamespacedTag(relationshipBStructField.Tag).get(cascadeTag), cascadeDelete) < 0 {
+			continue
+		}
+		metadata, err := nm.registry.get(elem(relationshipBStructField.Type))
+		if err != nil {
+			return nil, nil, err
+		}
+		rMetadata := metadata.(*relationshipMetadata)
+		fromNodeType := rMetadata.endpoints[startNode].Type
+		toNodeType := rMetadata.endpoints[endNode].Type
+
+		relatedNodeType := toNodeType
+		relationship := `-[:` + rMetadata.getStructLabel() + `]-`
+		if fromNodeType == nm._type && toNodeType != nm._type {
+			relationship += `>`
+		} else if toNodeType == nm._type && fromNodeType != nm._type {
+			relationship = `<` + relationship
+			relatedNodeType = fromNodeType
+		}
+		relatedMetadata, err := nm.registry.get(relatedNodeType)
+		if err != nil {
+			return nil, nil, err
+		}
+		relationships = append(relationships, relationship)
+		relatedMetadatas = append(relatedMetadatas, relatedMetadata)
+	}
+
+	return relationships, relatedMetadatas, nil
+}
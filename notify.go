@@ -22,13 +22,22 @@
 
 package gogm
 
+import "reflect"
+
+var typeOfBeforeSaver = reflect.TypeOf((*BeforeSaver)(nil)).Elem()
+var typeOfBeforeDeleter = reflect.TypeOf((*BeforeDeleter)(nil)).Elem()
+
 func notifyPreSaveGraph(g graph, eventer eventer, registry *registry) error {
+	if err := notifyPreSave(eventer, g, -1); err != nil {
+		return err
+	}
+	return setGraphLabelAndProperties(g, registry)
+}
 
-	if g.getValue().IsValid() {
-		for _, eventListener := range eventer.eventListeners {
-			eventListener.OnPreSave(event{g.getValue(), -1})
-		}
+//setGraphLabelAndProperties sets the label and the properties of g to the ones of its domain object
+func setGraphLabelAndProperties(g graph, registry *registry) error {
 
+	if g.getValue().IsValid() {
 		var (
 			metadata metadata
 			err      error
@@ -48,19 +57,79 @@ func notifyPreSaveGraph(g graph, eventer eventer, registry *registry) error {
 	return nil
 }
 
-func notifyPostSave(eventer eventer, g graph, lifeCycle lifeCycle) error {
+//notifyPreSave calls the BeforeSave callback of the domain object of g and the OnPreSave listeners, which can veto the save
+func notifyPreSave(eventer eventer, g graph, lifeCycle lifeCycle) error {
+	if g.getValue().IsValid() {
+		if beforeSaver, isBeforeSaver := g.getValue().Interface().(BeforeSaver); isBeforeSaver {
+			if err := beforeSaver.BeforeSave(); err != nil {
+				return err
+			}
+		}
+		for _, eventListener := range eventer.eventListeners {
+			if err := eventListener.OnPreSave(event{g.getValue(), lifeCycle}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//isSaveVetoable tells whether saving entities of metadata can be stopped by BeforeSave callbacks or vetoing listeners
+func isSaveVetoable(eventer eventer, metadata metadata) bool {
+	return eventer.hasVetoingEventListener() || metadata.getType().Implements(typeOfBeforeSaver)
+}
+
+//isDeleteVetoable tells whether deleting entities of metadata can be stopped by BeforeDelete callbacks or vetoing listeners
+func isDeleteVetoable(eventer eventer, metadata metadata) bool {
+	return eventer.hasVetoingEventListener() || metadata.getType().Implements(typeOfBeforeDeleter)
+}
+
+func notifyPostSave(eventer eventer, transaction *transaction, g graph, lifeCycle lifeCycle) error {
 	if g == nil {
 		return nil
 	}
 	if g.getValue().IsValid() {
+		e := event{g.getValue(), lifeCycle}
+		dispatch(transaction, func() {
+			if afterSaver, isAfterSaver := e.GetObject().(AfterSaver); isAfterSaver {
+				afterSaver.AfterSave()
+			}
+			for _, eventListener := range eventer.eventListeners {
+				eventListener.OnPostSave(e)
+			}
+		})
+	}
+	return nil
+}
+
+func notifyPostLoad(eventer eventer, g graph) {
+	if g.getValue().IsValid() {
+		if afterLoader, isAfterLoader := g.getValue().Interface().(AfterLoader); isAfterLoader {
+			afterLoader.AfterLoad()
+		}
 		for _, eventListener := range eventer.eventListeners {
-			eventListener.OnPostSave(event{g.getValue(), lifeCycle})
+			eventListener.OnPostLoad(event{object: g.getValue()})
+		}
+	}
+}
+
+func notifyPreDelete(eventer eventer, g graph) error {
+	if g.getValue().IsValid() {
+		if beforeDeleter, isBeforeDeleter := g.getValue().Interface().(BeforeDeleter); isBeforeDeleter {
+			if err := beforeDeleter.BeforeDelete(); err != nil {
+				return err
+			}
+		}
+		for _, eventListener := range eventer.eventListeners {
+			if err := eventListener.OnPreDelete(event{g.getValue(), DELETE}); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func notifyPostDelete(eventer eventer, g graph, lifeCycle lifeCycle) error {
+func notifyPostDelete(eventer eventer, transaction *transaction, g graph, lifeCycle lifeCycle) error {
 	if g == nil {
 		return nil
 	}
@@ -76,10 +145,22 @@ func notifyPostDelete(eventer eventer, g graph, lifeCycle lifeCycle) error {
 
 	//send notice
 	if g.getValue().IsValid() {
-		for _, eventListener := range eventer.eventListeners {
-			eventListener.OnPostDelete(event{g.getValue(), lifeCycle})
-		}
+		e := event{g.getValue(), lifeCycle}
+		dispatch(transaction, func() {
+			for _, eventListener := range eventer.eventListeners {
+				eventListener.OnPostDelete(e)
+			}
+		})
 	}
 
 	return nil
 }
+
+//dispatch sends notice, or buffers it till the commit of transaction
+func dispatch(transaction *transaction, notice func()) {
+	if transaction != nil {
+		transaction.bufferNotice(notice)
+		return
+	}
+	notice()
+}
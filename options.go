@@ -25,15 +25,108 @@ package gogm
 //LoadOptions represents options used for loading database objects
 type LoadOptions struct {
 	Depth int
+
+	//Filter restricts the entities loaded. Entities related to the loaded entities are not filtered
+	Filter *Filter
+
+	//SortOrders orders the loaded entities
+	SortOrders []SortOrder
+
+	//Pagination pages the loaded entities after they are filtered and sorted.
+	//Related entities are loaded to Depth for every entity of the page
+	Pagination *Pagination
+
+	//IncludeSoftDeleted loads soft deleted entities, they are excluded by default
+	IncludeSoftDeleted bool
+}
+
+//isRestricted tells whether the loaded entities are filtered, sorted or paged
+func (lo *LoadOptions) isRestricted() bool {
+	return lo.Filter != nil || len(lo.SortOrders) > 0 || lo.Pagination != nil
 }
 
 //SaveOptions represents options used for saving database objects
 type SaveOptions struct {
 	Depth int
+
+	//Merge saves new entities with MERGE instead of CREATE, matching existing entities by custom ID, or by unique
+	//properties when there's no custom ID. Matched entities are updated and get the ID of the existing entity.
+	//Versioned entities fail with ErrOptimisticLock unless they have the version of the entity they match
+	Merge bool
+
+	//MergeRelationships saves new relationships with MERGE instead of CREATE, matching existing relationships of the
+	//same type between the same nodes. Relationships of fields or relationship entities tagged 'merge' are always merged
+	MergeRelationships bool
+
+	//BatchSize, when greater than 0, creates new nodes and relationships with UNWIND statements of at most BatchSize
+	//entities, grouped by label and relationship type. Saves with a BatchSize run in a transaction
+	BatchSize int
+
+	//DryRun plans the save without saving. The database, the session and the IDs of the objects are left untouched.
+	//Before save callbacks and listeners aren't called, the plan is made from the objects as they are. The plan is set
+	//to Result
+	DryRun bool
+
+	//Result, when not nil, is set to what the save did, or would do on dry runs
+	Result *SaveResult
+}
+
+//SaveResult is what a save did, or would do on dry runs. Set SaveOptions.Result to get it
+type SaveResult struct {
+	//Statements is set on dry runs to the statements the save would run. In batched saves, new nodes are referenced
+	//by negative IDs in the rows creating new relationships
+	Statements []Statement
+
+	//Created, Updated and Unchanged are the objects reached by the save that were created, updated and left unchanged.
+	//Deleted are the relationship entities removed from saved nodes. Merged entities matching an existing entity are
+	//updated. Dry runs don't match them, they're reported created
+	Created   []interface{}
+	Updated   []interface{}
+	Unchanged []interface{}
+	Deleted   []interface{}
+
+	//SavedDepth is the depth of the entities reached by the save, from the saved objects
+	SavedDepth int
+
+	//Counters are the changes the save made to the database. It's nil on dry runs
+	Counters *Counters
 }
 
-//DeleteOptions represents options used for saving database objects. Currently, not applicatble to this version of the OGM
+//DeleteOptions represents options used for deleting database objects
 type DeleteOptions struct {
+	//Depth is the depth up to which the nodes related through relationship fields tagged 'cascade:delete' are deleted
+	//with a deleted node. 0, the default, deletes related nodes at any depth, and a negative depth deletes the node only
+	Depth int
+
+	//RemoveOrphans deletes cascaded nodes only when all the nodes relating them through the cascaded relationship are
+	//deleted. Nodes with another owner are kept
+	RemoveOrphans bool
+
+	//DryRun finds the objects to delete without deleting them. They're set to Result with the statements deleting them
+	DryRun bool
+
+	//Result, when not nil, is set to what the delete did, or would do on dry runs
+	Result *DeleteResult
+}
+
+//DeleteResult is what a delete did, or would do on dry runs. Set DeleteOptions.Result to get it
+type DeleteResult struct {
+	//Deleted are the deleted object and the related objects deleted with it. DeleteAll only sets it when it has to
+	//find the entities before deleting them: on dry runs, and for entities which cascade, are soft deleted or have
+	//pre delete callbacks or vetoing listeners
+	Deleted []interface{}
+
+	//Statements is set on dry runs to the statements that would delete the objects
+	Statements []Statement
+
+	//Counters are the changes the delete made to the database. It's nil on dry runs
+	Counters *Counters
+}
+
+//Statement is a Cypher statement with its parameters
+type Statement struct {
+	Cypher     string
+	Parameters map[string]interface{}
 }
 
 //NewLoadOptions creates LoadOptions with defaults
@@ -49,3 +142,36 @@ func NewSaveOptions() *SaveOptions {
 	so.Depth = 0
 	return so
 }
+
+//newResult resets Result for a save, and returns it. A result nobody reads is returned when Result is nil
+func (so *SaveOptions) newResult() *SaveResult {
+	result := so.Result
+	if result == nil {
+		result = &SaveResult{}
+	}
+	*result = SaveResult{}
+	if !so.DryRun {
+		result.Counters = &Counters{}
+	}
+	return result
+}
+
+//NewDeleteOptions creates DeleteOptions with defaults
+func NewDeleteOptions() *DeleteOptions {
+	do := &DeleteOptions{}
+	do.Depth = 0
+	return do
+}
+
+//newResult resets Result for a delete, and returns it. A result nobody reads is returned when Result is nil
+func (do *DeleteOptions) newResult() *DeleteResult {
+	result := do.Result
+	if result == nil {
+		result = &DeleteResult{}
+	}
+	*result = DeleteResult{}
+	if !do.DryRun {
+		result.Counters = &Counters{}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//SortDirection is the direction of a SortOrder
+type SortDirection int
+
+const (
+	//ASC sorts in ascending order
+	ASC SortDirection = iota
+
+	//DESC sorts in descending order
+	DESC
+)
+
+var sortDirections = map[SortDirection]string{
+	ASC:  "ASC",
+	DESC: "DESC"}
+
+//SortOrder orders loaded entities by the backend property PropertyName
+type SortOrder struct {
+	PropertyName string
+	Direction    SortDirection
+}
+
+//Pagination pages loaded entities. Skip and Limit are ignored when they are less than 1.
+//After holds the values of the sort order properties of the last entity of the previous page
+//for keyset pagination. It requires a value for every sort order
+type Pagination struct {
+	Skip  int64
+	Limit int64
+	After []interface{}
+}
+
+func getSortCypher(ref string, sortOrders []SortOrder, metadata metadata) (string, error) {
+	var orderBy []string
+	for _, sortOrder := range sortOrders {
+		property, err := getPropertyCypher(ref, sortOrder.PropertyName, metadata)
+		if err != nil {
+			return emptyString, err
+		}
+		orderBy = append(orderBy, property+` `+sortDirections[sortOrder.Direction])
+	}
+	if len(orderBy) == 0 {
+		return emptyString, nil
+	}
+	return `ORDER BY ` + strings.Join(orderBy, `, `), nil
+}
+
+//getKeysetCypher returns the predicate of the entities sorted after the values of the keyset pagination
+func getKeysetCypher(ref string, sortOrders []SortOrder, after []interface{}, metadata metadata, parameters map[string]interface{}) (string, error) {
+	if len(sortOrders) == 0 || len(sortOrders) != len(after) {
+		return emptyString, errors.New("Keyset pagination requires a value for every sort order")
+	}
+
+	var (
+		keyset     []string
+		equalities []string
+	)
+	for index, sortOrder := range sortOrders {
+		property, err := getPropertyCypher(ref, sortOrder.PropertyName, metadata)
+		if err != nil {
+			return emptyString, err
+		}
+		parameterName := "after" + strconv.Itoa(index)
+		parameters[parameterName] = after[index]
+
+		operator := comparisonOperators[GREATER_THAN]
+		if sortOrder.Direction == DESC {
+			operator = comparisonOperators[LESS_THAN]
+		}
+		keyset = append(keyset, `(`+strings.Join(append(equalities, property+` `+operator+` $`+parameterName), booleanOperators[andOperator])+`)`)
+		equalities = append(equalities, property+` `+comparisonOperators[EQUALS]+` $`+parameterName)
+	}
+	return `(` + strings.Join(keyset, booleanOperators[orOperator]) + `)`, nil
+}
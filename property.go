@@ -148,3 +148,22 @@ func getMapProperties(backendName string, structField *reflect.StructField, v re
 	}
 	return mappedProperties
 }
+
+//getPropertyCypher returns the cypher expression of the backend property propertyName of the entity referenced by ref
+func getPropertyCypher(ref string, propertyName string, metadata metadata) (string, error) {
+	propertyStructFields := metadata.getPropertyStructFields()
+	if propertyName == idPropertyName {
+		return `ID(` + ref + `)`, nil
+	}
+	if strings.Contains(propertyName, mapPropDelim) {
+		mappedPropName := strings.Split(propertyName, mapPropDelim)
+		if propertyStructFields[mappedPropName[0]] == nil || propertyStructFields[mappedPropName[0]].Type.Kind() != reflect.Map {
+			return emptyString, errors.New("Property '" + propertyName + "' isn't a mapped property of " + metadata.getType().String())
+		}
+		return ref + mapPropDelim + "`" + propertyName + "`", nil
+	}
+	if propertyStructFields[propertyName] == nil {
+		return emptyString, errors.New("Property '" + propertyName + "' isn't a property of " + metadata.getType().String())
+	}
+	return ref + mapPropDelim + propertyName, nil
+}
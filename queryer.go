@@ -23,6 +23,7 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -42,7 +43,7 @@ func newQueryer(cypherExecutor *cypherExecuter, graphFactory graphFactory, regis
 	return &queryer{cypherExecutor, graphFactory, registry}
 }
 
-func (q *queryer) queryForObject(object interface{}, cypher string, parameters map[string]interface{}) error {
+func (q *queryer) queryForObject(ctx context.Context, object interface{}, cypher string, parameters map[string]interface{}) error {
 	var (
 		err      error
 		values   reflect.Value
@@ -58,7 +59,7 @@ func (q *queryer) queryForObject(object interface{}, cypher string, parameters m
 	if label, err = metadata.getLabel(invalidValue); err != nil {
 		return err
 	}
-	if records, err = neo4j.Collect(q.cypherExecuter.exec(cypher, parameters)); err != nil {
+	if records, err = neo4j.Collect(q.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
 		return err
 	}
 
@@ -78,7 +79,7 @@ func (q *queryer) queryForObject(object interface{}, cypher string, parameters m
 
 }
 
-func (q *queryer) queryForObjects(objects interface{}, cypher string, parameters map[string]interface{}) error {
+func (q *queryer) queryForObjects(ctx context.Context, objects interface{}, cypher string, parameters map[string]interface{}) error {
 
 	var (
 		err      error
@@ -97,7 +98,7 @@ func (q *queryer) queryForObjects(objects interface{}, cypher string, parameters
 		return err
 	}
 
-	if records, err = neo4j.Collect(q.cypherExecuter.exec(cypher, parameters)); err != nil {
+	if records, err = neo4j.Collect(q.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
 		return err
 	}
 
@@ -108,7 +109,7 @@ func (q *queryer) queryForObjects(objects interface{}, cypher string, parameters
 	return nil
 }
 
-func (q *queryer) query(cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
+func (q *queryer) query(ctx context.Context, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
 
 	//registry all objects
 	for _, object := range objects {
@@ -117,7 +118,7 @@ func (q *queryer) query(cypher string, parameters map[string]interface{}, object
 		}
 	}
 
-	records, err := neo4j.Collect(q.cypherExecuter.exec(cypher, parameters))
+	records, err := neo4j.Collect(q.cypherExecuter.execContext(ctx, cypher, parameters))
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +267,7 @@ func (q *queryer) getObjectsFromRecords(domainObjectType reflect.Type, metadata
 	return ptrToObjs.Elem(), nil
 }
 
-func (q *queryer) countEntitiesOfType(object interface{}) (int64, error) {
+func (q *queryer) countEntitiesOfType(ctx context.Context, object interface{}) (int64, error) {
 
 	var (
 		value         = reflect.ValueOf(object)
@@ -290,7 +291,7 @@ func (q *queryer) countEntitiesOfType(object interface{}) (int64, error) {
 	cypher, parameters = cypherBuilder.getCountEntitiesOfType()
 
 	if cypher != emptyString {
-		if record, err = neo4j.Single(q.cypherExecuter.exec(cypher, parameters)); err != nil {
+		if record, err = neo4j.Single(q.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
 			return -1, err
 		}
 		if record != nil {
@@ -301,12 +302,12 @@ func (q *queryer) countEntitiesOfType(object interface{}) (int64, error) {
 	return count, nil
 }
 
-func (q *queryer) count(cypher string, parameters map[string]interface{}) (int64, error) {
+func (q *queryer) count(ctx context.Context, cypher string, parameters map[string]interface{}) (int64, error) {
 	var (
 		record neo4j.Record
 		err    error
 	)
-	if record, err = neo4j.Single(q.cypherExecuter.exec(cypher, parameters)); err != nil {
+	if record, err = neo4j.Single(q.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
 		return -1, err
 	}
 	return record.GetByIndex(0).(int64), nil
@@ -40,6 +40,9 @@ type relationship struct {
 	signature string
 
 	nodes map[int64]graph
+
+	//merge tells whether the relationship is merged between its nodes when it's new
+	merge bool
 }
 
 type direction int
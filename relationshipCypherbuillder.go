@@ -30,13 +30,14 @@ type relationshipQueryBuilder struct {
 	r               *relationship
 	registry        *registry
 	deltaProperties map[string]interface{}
+	versionCheck    *versionCheck
 }
 
 func (rqb relationshipQueryBuilder) getGraph() graph {
 	return rqb.r
 }
 
-func newRelationshipCypherBuilder(r *relationship, registry *registry, stored graph) relationshipQueryBuilder {
+func newRelationshipCypherBuilder(r *relationship, registry *registry, stored graph, versionCheck *versionCheck) relationshipQueryBuilder {
 	deltaProperties := r.getProperties()
 	if stored != nil {
 		deltaProperties = diffProperties(deltaProperties, stored.getProperties())
@@ -44,7 +45,8 @@ func newRelationshipCypherBuilder(r *relationship, registry *registry, stored gr
 	return relationshipQueryBuilder{
 		r,
 		registry,
-		deltaProperties}
+		deltaProperties,
+		versionCheck}
 }
 
 func (rqb relationshipQueryBuilder) getRemovedGraphs() (map[int64]graph, map[int64]graph) {
@@ -55,16 +57,105 @@ func (rqb relationshipQueryBuilder) isGraphDirty() bool {
 	return rqb.r.getID() < 0 || len(rqb.deltaProperties) > 0
 }
 
-func (rqb relationshipQueryBuilder) getCreate() (string, string, map[string]interface{}, map[string]graph) {
+//getMerge tells whether r is merged between its nodes, and returns the properties r is merged on
+func (rqb relationshipQueryBuilder) getMerge(saveOptions *SaveOptions) (bool, []string, map[string]interface{}) {
 	var (
-		r         = rqb.r
-		startSign = r.nodes[startNode].getSignature()
-		endSign   = r.nodes[endNode].getSignature()
-		rSign     = r.getSignature()
+		r     = rqb.r
+		merge = r.merge || saveOptions.MergeRelationships
+	)
+	if r.getValue().IsValid() {
+		metadata, _ := rqb.registry.get(r.getValue().Type())
+		if relationshipMetadata, isRelationshipMetadata := metadata.(*relationshipMetadata); isRelationshipMetadata {
+			merge = merge || relationshipMetadata.merge
+			if merge && len(relationshipMetadata.mergeBackendNames) > 0 {
+				if properties := getMergeProperties(r, relationshipMetadata.mergeBackendNames); properties != nil {
+					return true, relationshipMetadata.mergeBackendNames, properties
+				}
+				//Relationships with null merge properties are created
+				merge = false
+			}
+		}
+		if saveOptions.Merge {
+			if properties := getMergeProperties(r, metadata.getMergePropertyNames()); properties != nil {
+				return true, metadata.getMergePropertyNames(), properties
+			}
+		}
+	}
+	return merge, nil, nil
+}
+
+//getMergeMatch returns the clauses matching the relationship a new relationship is merged on, to check its version
+//and tell whether the merge created the relationship. It's empty for relationships that aren't merged
+func (rqb relationshipQueryBuilder) getMergeMatch(saveOptions *SaveOptions) (string, map[string]interface{}) {
+	var (
+		r                       = rqb.r
+		rSign                   = r.getSignature()
+		matchedRef              = rSign + "Matched"
+		merge, propertyNames, _ = rqb.getMerge(saveOptions)
+	)
+	if !merge || r.getID() >= 0 {
+		return emptyString, nil
+	}
+	pattern, parameters := getMergePattern(r, propertyNames)
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+	match := `WITH *
+	OPTIONAL MATCH (` + r.nodes[startNode].getSignature() + `)-[` + matchedRef + `:` + r.getType() + pattern + `]->(` + r.nodes[endNode].getSignature() + `)
+	`
+	if rqb.versionCheck != nil {
+		match += getMergeVersionCheck(matchedRef, rSign, rqb.versionCheck, parameters)
+	}
+	return match, parameters
+}
+
+func (rqb relationshipQueryBuilder) getCreate(saveOptions *SaveOptions) (string, string, map[string]interface{}, map[string]graph) {
+	var (
+		r                       = rqb.r
+		startSign               = r.nodes[startNode].getSignature()
+		endSign                 = r.nodes[endNode].getSignature()
+		rSign                   = r.getSignature()
+		pattern                 string
+		parameters              map[string]interface{}
+		operation               = `CREATE`
+		merge, propertyNames, _ = rqb.getMerge(saveOptions)
+	)
+	if merge {
+		operation = `MERGE`
+		pattern, parameters = getMergePattern(r, propertyNames)
+	}
+	create := operation + ` (` + startSign + `)-[` + rSign + `:` + r.getType() + pattern + `]->(` + endSign + `)
+	`
+	return "", create, parameters, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
+}
+
+//getUnwindCreate returns the row of r without its start and end node IDs, they are only known once new nodes are created
+func (rqb relationshipQueryBuilder) getUnwindCreate(saveOptions *SaveOptions) (string, map[string]interface{}) {
+	var (
+		r                                = rqb.r
+		create                           = `CREATE`
+		row                              = map[string]interface{}{"ref": r.getSignature(), "properties": rqb.getSetProperties()}
+		_return                          = `RETURN row.ref, ID(r)`
+		merge, propertyNames, properties = rqb.getMerge(saveOptions)
+		pattern                          = getUnwindMergePattern(propertyNames)
 	)
-	create := `CREATE (` + startSign + `)-[` + rSign + `:` + r.getType() + `]->(` + endSign + `)
+	if merge {
+		create = `OPTIONAL MATCH (s)-[matched:` + r.getType() + pattern + `]->(e)
 	`
-	return "", create, nil, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
+		if rqb.versionCheck != nil {
+			create += getUnwindMergeVersionCheck("row, s, e, matched", rqb.versionCheck)
+			row["version"] = rqb.versionCheck.version
+		}
+		create += `MERGE`
+		row["merge"] = properties
+		_return += `, matched IS NULL`
+	}
+	return `UNWIND $rows AS row
+	MATCH (s) WHERE ID(s) = row.start
+	MATCH (e) WHERE ID(e) = row.end
+	` + create + ` (s)-[r:` + r.getType() + pattern + `]->(e)
+	SET r += row.properties
+	` + _return, row
 }
 
 func (rqb relationshipQueryBuilder) getMatch() (string, map[string]interface{}, map[string]graph) {
@@ -75,24 +166,44 @@ func (rqb relationshipQueryBuilder) getMatch() (string, map[string]interface{},
 		rSign     = r.getSignature()
 		match     = `MATCH (` + startSign + `)-[` + rSign + `:` + r.getType() + `]->(` + endSign + `)
 		`
+		parameters map[string]interface{}
 	)
-	return match, nil, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
+	if rqb.versionCheck != nil && r.getID() >= 0 && rqb.isGraphDirty() {
+		versionCQLRef := rSign + "Version"
+		match += `WHERE coalesce(` + rSign + `.` + rqb.versionCheck.propertyName + `, 0) = $` + versionCQLRef + `
+		`
+		parameters = map[string]interface{}{versionCQLRef: rqb.versionCheck.version}
+	}
+	return match, parameters, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
 }
 
-func (rqb relationshipQueryBuilder) getSet() (string, map[string]interface{}) {
+//getSetProperties returns the properties set on save, with the next version of versioned relationships
+func (rqb relationshipQueryBuilder) getSetProperties() map[string]interface{} {
 	var (
 		r          = rqb.r
-		rSign      = r.getSignature()
 		properties = map[string]interface{}{}
-		parameters = map[string]interface{}{}
-		propCQLRef = rSign + "Properties"
-		set        string
 	)
 	for propertyName, propertyValue := range r.getProperties() {
 		if !metaProperties[propertyName] {
 			properties[propertyName] = propertyValue
 		}
 	}
+	if rqb.versionCheck != nil {
+		version := rqb.versionCheck.next()
+		r.getProperties()[rqb.versionCheck.propertyName] = version
+		properties[rqb.versionCheck.propertyName] = version
+	}
+	return properties
+}
+
+func (rqb relationshipQueryBuilder) getSet() (string, map[string]interface{}) {
+	var (
+		rSign      = rqb.r.getSignature()
+		properties = rqb.getSetProperties()
+		parameters = map[string]interface{}{}
+		propCQLRef = rSign + "Properties"
+		set        string
+	)
 
 	if len(properties) > 0 {
 		set += `SET ` + rSign + ` += $` + propCQLRef + `
@@ -103,39 +214,38 @@ func (rqb relationshipQueryBuilder) getSet() (string, map[string]interface{}) {
 	return set, parameters
 }
 
-func (rqb relationshipQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}) {
+func (rqb relationshipQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error) {
 
 	var (
 		depth                   = strconv.Itoa(lo.Depth)
 		metadata, _             = rqb.registry.get(rqb.r.getValue().Type())
 		customIDPropertyName, _ = metadata.getCustomID(*rqb.r.getValue())
-		parameters              = map[string]interface{}{}
 	)
 
 	if lo.Depth == infiniteDepth {
 		depth = ""
 	}
 
-	match := `MATCH path = ()-[*0..` + depth + `]-()-[r:` + rqb.r.getLabel() + `]-()-[*0..` + depth + `]-()
-	`
-
-	var filter string
-	if IDs != nil {
-		filter = `WHERE ID(r) IN $ids 
-		`
-		if customIDPropertyName != emptyString {
-			filter = `WHERE r.` + customIDPropertyName + ` IN $ids 
-			`
-		}
-		parameters["ids"] = IDs
+	filter, page, orderBy, parameters, err := getLoadAllRootClauses("r", IDs, customIDPropertyName, lo, metadata, rqb.registry)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	pathFilter, err := getLoadAllPathFilter(lo, metadata, rqb.registry)
+	if err != nil {
+		return emptyString, nil, err
 	}
 
+	match := `MATCH ()-[r:` + rqb.r.getLabel() + `]->()
+	`
+	expand := `MATCH path = ()-[*0..` + depth + `]-()-[r]-()-[*0..` + depth + `]-()
+	`
+
 	end := `WITH r, path, range(0, length(path) - 1) as index
 	WITH  r, path, index, [i in index | CASE WHEN nodes(path)[i] = startNode(relationships(path)[i]) THEN false ELSE true END] as isDirectionInverted
 	RETURN path, ID(r), isDirectionInverted
-	`
+	` + orderBy
 
-	return match + filter + end, parameters
+	return match + filter + page + expand + pathFilter + end, parameters, nil
 }
 
 func (rqb relationshipQueryBuilder) getDeleteAll() (string, map[string]interface{}) {
@@ -152,6 +262,37 @@ func (rqb relationshipQueryBuilder) getDelete() (string, map[string]interface{},
 	return delete, nil, depedencies
 }
 
+func (rqb relationshipQueryBuilder) getSoftDelete(softDeleteValue interface{}) (string, map[string]interface{}, map[string]graph) {
+	var (
+		rSign                               = rqb.r.getSignature()
+		metadata, _                         = rqb.registry.get(rqb.r.getValue().Type())
+		softDeleteCQLRef                    = rSign + "SoftDelete"
+		softDelete, parameters, depedencies = rqb.getMatch()
+	)
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+	parameters[softDeleteCQLRef] = softDeleteValue
+	softDelete += `SET ` + rSign + `.` + metadata.getSoftDeleteBackendName() + ` = $` + softDeleteCQLRef + ` RETURN ID(` + rSign + `)
+	`
+	return softDelete, parameters, depedencies
+}
+
+func (rqb relationshipQueryBuilder) getSoftDeleteAll(softDeleteValue interface{}) (string, map[string]interface{}) {
+	metadata, _ := rqb.registry.get(rqb.r.getValue().Type())
+	return `MATCH ()-[r:` + rqb.r.getType() + `]->()
+	WHERE ` + getNotSoftDeletedPredicate("r", metadata) + `
+	SET r.` + metadata.getSoftDeleteBackendName() + ` = $softDelete
+	RETURN ID(r)`, map[string]interface{}{"softDelete": softDeleteValue}
+}
+
 func (rqb relationshipQueryBuilder) getCountEntitiesOfType() (string, map[string]interface{}) {
-	return `MATCH ()-[r:` + rqb.r.getType() + `]->() RETURN count(r) as count`, nil
+	var (
+		metadata, _ = rqb.registry.get(rqb.r.getValue().Type())
+		filter      string
+	)
+	if predicate := getNotSoftDeletedPredicate("r", metadata); predicate != emptyString {
+		filter = ` WHERE ` + predicate
+	}
+	return `MATCH ()-[r:` + rqb.r.getType() + `]->()` + filter + ` RETURN count(r) as count`, nil
 }
@@ -32,6 +32,10 @@ const relationshipRelatedGraphLen = 2
 type relationshipMetadata struct {
 	commonMetadata
 	endpoints map[int64]reflect.StructField
+
+	//merge tells whether new relationships are merged between their endpoints, on the properties in mergeBackendNames
+	merge             bool
+	mergeBackendNames []string
 }
 
 func newRelationshipMetadata() *relationshipMetadata {
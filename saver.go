@@ -23,6 +23,7 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"strings"
@@ -42,15 +43,17 @@ func newSaver(cypherExecuter *cypherExecuter, store store, eventer eventer, regi
 	return &saver{cypherExecuter, store, eventer, registry, graphFactory}
 }
 
-func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
+func (s *saver) save(ctx context.Context, object interface{}, saveOptions *SaveOptions) error {
 	var (
-		graphs        []graph
-		record        neo4j.Record
-		savedGraphs   map[string]graph
-		deletedGraphs map[string]graph
-		err           error
-		store         = s.store
-		savedDepths   []int
+		graphs          []graph
+		record          neo4j.Record
+		savedGraphs     map[string]graph
+		deletedGraphs   map[string]graph
+		unchangedGraphs map[string]graph
+		err             error
+		store           = s.store
+		savedDepths     []int
+		batch           *saveBatch
 	)
 
 	if saveOptions == nil {
@@ -62,16 +65,43 @@ func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
 		return errors.New("Cannot save greater than max depth")
 	}
 
+	result := saveOptions.newResult()
+
 	if graphs, err = s.graphFactory.get(reflect.ValueOf(object), nil); err != nil {
 		return err
 	}
 
-	if savedDepths, record, savedGraphs, deletedGraphs, err = s.persist(graphs, saveOptions); err != nil {
+	if saveOptions.Merge {
+		if err = s.validateMerge(graphs); err != nil {
+			return err
+		}
+	}
+
+	if saveOptions.BatchSize > 0 {
+		batch = newSaveBatch(saveOptions.BatchSize)
+	}
+
+	if savedDepths, record, savedGraphs, deletedGraphs, unchangedGraphs, err = s.persist(ctx, graphs, saveOptions, result, batch); err != nil {
 		return err
 	}
+	if saveOptions.DryRun {
+		return nil
+	}
+
+	createdGraphSignatures := map[string]bool{}
+	created := func(g graph) {
+		createdGraphSignatures[g.getSignature()] = true
+		if s.cypherExecuter.transaction != nil {
+			s.cypherExecuter.transaction.addCreatedGraph(g)
+		}
+	}
+	if batch != nil {
+		for _, createdGraph := range batch.createdGraphs {
+			created(createdGraph)
+		}
+	}
 
 	if record != nil {
-		createdGraphSignatures := map[string]bool{}
 		for index, key := range record.Keys() {
 			properties := record.GetByIndex(index).(map[string]interface{})
 
@@ -79,55 +109,61 @@ func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
 			if savedGraphs[key] != nil && savedGraphs[key].getID() < 0 {
 				id := properties[idPropertyName].(int64)
 				unloadGraphID(savedGraphs[key], &id)
-				createdGraphSignatures[savedGraphs[key].getSignature()] = true
+				//Merged graphs matching an entity are updated
+				if isCreated, isMerged := properties[createdPropertyName].(bool); !isMerged || isCreated {
+					created(savedGraphs[key])
+				}
 			}
 
 			if deletedGraphs[key] != nil {
 				//deletedGraphs[key] has been deleted. Update the local store and notify objects
 				for _, relatedGraph := range deletedGraphs[key].getRelatedGraphs() {
 					delete(store.get(relatedGraph).getRelatedGraphs(), deletedGraphs[key].getID())
-					notifyPostSave(s.eventer, relatedGraph, UPDATE)
+					notifyPostSave(s.eventer, s.cypherExecuter.transaction, relatedGraph, UPDATE)
 				}
 				store.delete(deletedGraphs[key])
-				notifyPostDelete(s.eventer, deletedGraphs[key], DELETE)
+				notifyPostDelete(s.eventer, s.cypherExecuter.transaction, deletedGraphs[key], DELETE)
 			}
 		}
+	}
 
-		for _, g := range savedGraphs {
-			for internalID, relatedGraph := range g.getRelatedGraphs() {
-				if internalID < 0 {
-					//Related graph map is still referencing the tempoary ID. Update with database generated IDs
-					delete(g.getRelatedGraphs(), internalID)
-					if relatedGraph.getID() > initialGraphID {
-						g.setRelatedGraph(relatedGraph)
-					}
+	for _, g := range savedGraphs {
+		for internalID, relatedGraph := range g.getRelatedGraphs() {
+			if internalID < 0 {
+				//Related graph map is still referencing the tempoary ID. Update with database generated IDs
+				delete(g.getRelatedGraphs(), internalID)
+				if relatedGraph.getID() > initialGraphID {
+					g.setRelatedGraph(relatedGraph)
 				}
 			}
+		}
 
-			savedDepth := savedDepths[g.getCoordinate().graphIndex]
-			if savedDepth >= 0 {
-				if g.getCoordinate().depth == 0 {
-					g.setDepth(&savedDepth)
-				}
-				saveLifecycle := UPDATE
-				if createdGraphSignatures[g.getSignature()] {
-					saveLifecycle = CREATE
-				}
-				store.save(g)
-				if g.getValue().IsValid() {
-					for _, eventListener := range s.eventer.eventListeners {
-						eventListener.OnPostSave(event{g.getValue(), saveLifecycle})
-					}
-				}
-
+		savedDepth := savedDepths[g.getCoordinate().graphIndex]
+		if savedDepth >= 0 {
+			if g.getCoordinate().depth == 0 {
+				g.setDepth(&savedDepth)
+			}
+			saveLifecycle := UPDATE
+			if createdGraphSignatures[g.getSignature()] {
+				saveLifecycle = CREATE
 			}
+			if err = unloadGraphVersion(g, s.registry); err != nil {
+				return err
+			}
+			store.save(g)
+			notifyPostSave(s.eventer, s.cypherExecuter.transaction, g, saveLifecycle)
+
 		}
 	}
 
+	setSaveResult(result, savedGraphs, unchangedGraphs, deletedGraphs, savedDepths, func(g graph) bool {
+		return createdGraphSignatures[g.getSignature()]
+	})
+
 	return err
 }
 
-func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, neo4j.Record, map[string]graph, map[string]graph, error) {
+func (s *saver) persist(ctx context.Context, graphs []graph, saveOptions *SaveOptions, result *SaveResult, batch *saveBatch) ([]int, neo4j.Record, map[string]graph, map[string]graph, map[string]graph, error) {
 
 	var (
 		err    error
@@ -136,28 +172,40 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, neo4j.
 
 		loadedGraphs = newstore(nil)
 
-		savedGraphs   map[string]graph
-		deletedGraphs map[string]graph
-
-		grandParams        = map[string]interface{}{}
-		grandSavedGraphs   = map[string]graph{}
-		grandDeletedGraphs = map[string]graph{}
-		saveClausesSlice   []clauses
-		savedDepths        []int
-		ensureID           = getIDer(&internalIDGenerator{initialGraphID}, s.store)
+		savedGraphs     map[string]graph
+		deletedGraphs   map[string]graph
+		unchangedGraphs map[string]graph
+		mergedGraphs    map[string]graph
+
+		grandParams          = map[string]interface{}{}
+		grandSavedGraphs     = map[string]graph{}
+		grandDeletedGraphs   = map[string]graph{}
+		grandUnchangedGraphs = map[string]graph{}
+		grandMergedGraphs    = map[string]graph{}
+		saveClausesSlice     []clauses
+		savedDepths          []int
+		ensureID             = getIDer(&internalIDGenerator{initialGraphID}, s.store)
 	)
 
+	if saveOptions.DryRun {
+		//Dry runs leave the IDs of the objects untouched
+		defer clearInternalIDs(loadedGraphs)
+	}
+
 	for index, graph := range graphs {
+		if err = ctx.Err(); err != nil {
+			return savedDepths, nil, nil, nil, nil, err
+		}
 		ensureID(graph)
-		for _, rg := range graph.getRelatedGraphs() {
+		for _, rg := range getSortedGraphs(graph.getRelatedGraphs()) {
 			ensureID(rg)
 		}
 		graph.setCoordinate(&coordinate{0, index})
 
 		var graphSaveClauses clauses
 		var savedDepth int
-		if savedDepth, graphSaveClauses, savedGraphs, deletedGraphs, params, err = s.getSaveMeta(graph, saveOptions, ensureID, loadedGraphs); err != nil {
-			return savedDepths, nil, nil, nil, err
+		if savedDepth, graphSaveClauses, savedGraphs, deletedGraphs, unchangedGraphs, mergedGraphs, params, err = s.getSaveMeta(graph, saveOptions, ensureID, loadedGraphs, batch); err != nil {
+			return savedDepths, nil, nil, nil, nil, err
 		}
 
 		savedDepths = append(savedDepths, savedDepth)
@@ -175,6 +223,14 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, neo4j.
 		for cqlref, graph := range deletedGraphs {
 			grandDeletedGraphs[cqlref] = graph
 		}
+
+		for cqlref, graph := range unchangedGraphs {
+			grandUnchangedGraphs[cqlref] = graph
+		}
+
+		for cqlref, graph := range mergedGraphs {
+			grandMergedGraphs[cqlref] = graph
+		}
 	}
 
 	var grandSaveClauses = make(clauses)
@@ -194,8 +250,13 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, neo4j.
 				begin = `return `
 			}
 			_return += begin
-			for entityCQLRef := range graphGroup {
-				_return += entityCQLRef + `{` + idPropertyName + `:ID(` + entityCQLRef + `)},`
+			for _, entityCQLRef := range getSortedSignatures(graphGroup) {
+				created := emptyString
+				if grandMergedGraphs[entityCQLRef] != nil {
+					//Merged graphs are created when they don't match an entity
+					created = `, ` + createdPropertyName + `:` + entityCQLRef + `Matched IS NULL`
+				}
+				_return += entityCQLRef + `{` + idPropertyName + `:ID(` + entityCQLRef + `)` + created + `},`
 			}
 			_return = strings.TrimSuffix(_return, ",")
 		}
@@ -203,23 +264,115 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, neo4j.
 
 	cypher += _return
 
+	if saveOptions.DryRun {
+		s.plan(result, cypher, grandParams, grandSavedGraphs, grandDeletedGraphs, grandUnchangedGraphs, savedDepths, batch)
+		return savedDepths, nil, grandSavedGraphs, grandDeletedGraphs, grandUnchangedGraphs, nil
+	}
+
+	if batch != nil {
+		if err = batch.create(ctx, s.cypherExecuter, result.Counters); err != nil {
+			return savedDepths, nil, nil, nil, nil, err
+		}
+		for _, createdGraph := range append(batch.createdGraphs, batch.mergedGraphs...) {
+			grandSavedGraphs[createdGraph.getSignature()] = createdGraph
+		}
+	}
+
 	if cypher != emptyString {
 		var records []neo4j.Record
-		if records, err = neo4j.Collect(s.cypherExecuter.exec(cypher, grandParams)); err != nil {
-			return savedDepths, nil, nil, nil, err
+		if records, err = result.Counters.collect(s.cypherExecuter.execContext(ctx, cypher, grandParams)); err != nil {
+			return savedDepths, nil, nil, nil, nil, err
+		}
+		if len(records) == 0 {
+			//A MATCH didn't match. Versioned entities failed the version check
+			if hasVersionedGraph(grandSavedGraphs, grandMergedGraphs, s.registry) {
+				return savedDepths, nil, nil, nil, nil, ErrOptimisticLock
+			}
+			return savedDepths, nil, nil, nil, nil, errors.New("Entities to save weren't found in the database")
 		}
 		record = records[0]
 	}
 
-	return savedDepths, record, grandSavedGraphs, grandDeletedGraphs, err
+	return savedDepths, record, grandSavedGraphs, grandDeletedGraphs, grandUnchangedGraphs, err
+}
+
+//validateMerge checks the entities of graphs can be merged. Without a custom ID, an entity with several unique
+//properties could match a different entity on each of them, and merging it on all of them would break the constraints
+func (s *saver) validateMerge(graphs []graph) error {
+	for _, g := range graphs {
+		if g.getValue() == nil || !g.getValue().IsValid() {
+			continue
+		}
+		metadata, err := s.registry.get(g.getValue().Type())
+		if err != nil {
+			return err
+		}
+		if len(metadata.getMergePropertyNames()) > 1 {
+			return errors.New("Entities of type " + g.getValue().Type().String() + " can't be merged. They have several unique properties and no custom ID")
+		}
+	}
+	return nil
+}
+
+//plan sets the statements of a dry run save to result, with the objects the save would create, update and delete
+func (s *saver) plan(result *SaveResult, cypher string, parameters map[string]interface{}, savedGraphs map[string]graph, deletedGraphs map[string]graph, unchangedGraphs map[string]graph, savedDepths []int, batch *saveBatch) {
+	var statements []Statement
+	if batch != nil {
+		statements = batch.getStatements()
+		for _, g := range batch.getGraphs() {
+			savedGraphs[g.getSignature()] = g
+		}
+	}
+	if cypher != emptyString {
+		statements = append(statements, Statement{cypher, parameters})
+	}
+
+	result.Statements = statements
+	setSaveResult(result, savedGraphs, unchangedGraphs, deletedGraphs, savedDepths, func(g graph) bool {
+		return g.getID() < 0
+	})
+}
+
+//setSaveResult sets the objects reached by a save to result, in the order of their signatures, with the depth saved.
+//Saved graphs are updated unless isCreated or unchanged. Unchanged graphs are saved when other graphs depend on them
+func setSaveResult(result *SaveResult, savedGraphs map[string]graph, unchangedGraphs map[string]graph, deletedGraphs map[string]graph, savedDepths []int, isCreated func(graph) bool) {
+	var created, updated, unchanged, deleted []graph
+	for _, signature := range getSortedSignatures(savedGraphs) {
+		if g := savedGraphs[signature]; isCreated(g) {
+			created = append(created, g)
+		} else if unchangedGraphs[signature] == nil {
+			updated = append(updated, g)
+		}
+	}
+	for _, signature := range getSortedSignatures(unchangedGraphs) {
+		unchanged = append(unchanged, unchangedGraphs[signature])
+	}
+	for _, signature := range getSortedSignatures(deletedGraphs) {
+		deleted = append(deleted, deletedGraphs[signature])
+	}
+
+	result.Created = getObjects(created)
+	result.Updated = getObjects(updated)
+	result.Unchanged = getObjects(unchanged)
+	result.Deleted = getObjects(deleted)
+
+	//Graph depths count relationships and nodes. Saved depths count nodes, like load depths
+	result.SavedDepth = 0
+	for _, savedDepth := range savedDepths {
+		if savedDepth/2 > result.SavedDepth {
+			result.SavedDepth = savedDepth / 2
+		}
+	}
 }
 
-func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(graph), loadedGraphs store) (int, map[clause][]string, map[string]graph, map[string]graph, map[string]interface{}, error) {
+func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(graph), loadedGraphs store, batch *saveBatch) (int, map[clause][]string, map[string]graph, map[string]graph, map[string]graph, map[string]graph, map[string]interface{}, error) {
 	var (
 		err error
 
 		savedGraphs      = map[string]graph{}
 		deletedGraphs    = map[string]graph{}
+		unchangedGraphs  = map[string]graph{}
+		mergedGraphs     = map[string]graph{}
 		gotten           = map[string]graphQueryBuilder{}
 		parameters       = []map[string]interface{}{}
 		graphSaveClauses = map[clause][]string{}
@@ -234,7 +387,7 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 	}
 
 	if g.getID() == initialGraphID {
-		return savedDepth, nil, nil, nil, nil, nil
+		return savedDepth, nil, nil, nil, nil, nil, nil, nil
 	}
 
 	queue := []graph{g}
@@ -245,25 +398,44 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 
 		savedDepth = queue[0].getCoordinate().depth
 
-		if err = notifyPreSaveGraph(queue[0], s.eventer, s.registry); err != nil {
-			return savedDepth, nil, nil, nil, nil, err
+		if saveOptions.DryRun {
+			//Callbacks and listeners aren't called for saves which don't happen
+			err = setGraphLabelAndProperties(queue[0], s.registry)
+		} else {
+			err = notifyPreSaveGraph(queue[0], s.eventer, s.registry)
+		}
+		if err != nil {
+			return savedDepth, nil, nil, nil, nil, nil, nil, err
 		}
 
 		if reflect.TypeOf(queue[0]) == typeOfPrivateRelationship || queue[0].getCoordinate().depth+1 < maxGraphDepth {
 			if err := loadRelatedGraphs(queue[0], ensureID, s.registry, loadedGraphs, s.store); err != nil {
-				return savedDepth, nil, nil, nil, nil, err
+				return savedDepth, nil, nil, nil, nil, nil, nil, err
 			}
 		}
 
 		var cBuilder graphQueryBuilder
 		if cBuilder, err = newCypherBuilder(queue[0], s.registry, s.store); err != nil {
-			return savedDepth, nil, nil, nil, nil, err
+			return savedDepth, nil, nil, nil, nil, nil, nil, err
 		}
-		if cBuilder.isGraphDirty() {
+		if queue[0].getID() < 0 && batch != nil {
+			//New graphs are created in batches before the other graphs are saved
+			batch.add(queue[0], cBuilder, saveOptions)
+		} else if cBuilder.isGraphDirty() {
 
 			if queue[0].getID() < 0 {
-				nodeCreate, relationshipCreate, createParameters, createDeps := cBuilder.getCreate()
+				nodeCreate, relationshipCreate, createParameters, createDeps := cBuilder.getCreate(saveOptions)
 				parameters = append(parameters, createParameters)
+				if mergeMatch, mergeMatchParameters := cBuilder.getMergeMatch(saveOptions); mergeMatch != emptyString {
+					//Nodes are matched with the other matched graphs, relationships once their nodes are created
+					parameters = append(parameters, mergeMatchParameters)
+					if nodeCreate != emptyString {
+						graphSaveClauses[matchClause] = append(graphSaveClauses[matchClause], mergeMatch)
+					} else {
+						relationshipCreate = mergeMatch + relationshipCreate
+					}
+					mergedGraphs[queue[0].getSignature()] = queue[0]
+				}
 				if nodeCreate != emptyString {
 					graphSaveClauses[nodeCreateClause] = append(graphSaveClauses[nodeCreateClause], nodeCreate)
 				}
@@ -286,15 +458,15 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 			if queue[0].getCoordinate().depth+1 < maxGraphDepth {
 				removedRelationships, otherNodes := cBuilder.getRemovedGraphs()
 
-				for _, removedRelationship := range removedRelationships {
+				for _, removedRelationship := range getSortedGraphs(removedRelationships) {
 
 					otherNode := otherNodes[removedRelationship.getID()]
 					var removedCBuilder, otherGraphCBuilder graphQueryBuilder
 					if removedCBuilder, err = newCypherBuilder(removedRelationship, s.registry, nil); err != nil {
-						return savedDepth, nil, nil, nil, nil, err
+						return savedDepth, nil, nil, nil, nil, nil, nil, err
 					}
 					if otherGraphCBuilder, err = newCypherBuilder(otherNode, s.registry, nil); err != nil {
-						return savedDepth, nil, nil, nil, nil, err
+						return savedDepth, nil, nil, nil, nil, nil, nil, err
 					}
 
 					match, matchParameters, matchDeps := removedCBuilder.getMatch()
@@ -310,15 +482,21 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 					graphSaveClauses[deleteClause] = append(graphSaveClauses[deleteClause], "DELETE "+removedRelationship.getSignature()+"\n")
 
 					deletedGraphs[removedRelationship.getSignature()] = removedRelationship
+					if savedGraphs[otherNode.getSignature()] == nil {
+						unchangedGraphs[otherNode.getSignature()] = otherNode
+					}
 					savedGraphs[otherNode.getSignature()] = otherNode
 				}
 			}
+			delete(unchangedGraphs, queue[0].getSignature())
 			savedGraphs[queue[0].getSignature()] = queue[0]
+		} else {
+			unchangedGraphs[queue[0].getSignature()] = queue[0]
 		}
 
 		gotten[queue[0].getSignature()] = cBuilder
 
-		for _, relatedGraph := range queue[0].getRelatedGraphs() {
+		for _, relatedGraph := range getSortedGraphs(queue[0].getRelatedGraphs()) {
 			if gotten[relatedGraph.getSignature()] == nil && relatedGraph.getID() != initialGraphID {
 				queue = append(queue, relatedGraph)
 			}
@@ -331,7 +509,7 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 	//but node's aren't dirty, node match have to be included to match
 	//the relationship for update
 	for _, dep := range depedencies {
-		for ID := range dep {
+		for _, ID := range getSortedSignatures(dep) {
 			if savedGraphs[ID] == nil {
 				match, matchParameters, _ := gotten[ID].getMatch()
 				parameters = append(parameters, matchParameters)
@@ -341,7 +519,7 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 		}
 	}
 
-	return savedDepth, graphSaveClauses, savedGraphs, deletedGraphs, flattenParamters(parameters), err
+	return savedDepth, graphSaveClauses, savedGraphs, deletedGraphs, unchangedGraphs, mergedGraphs, flattenParamters(parameters), err
 }
 
 func loadRelatedGraphs(g graph, ID func(graph), registry *registry, loadedGraphs store, local store) error {
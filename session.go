@@ -22,18 +22,36 @@
 
 package gogm
 
-//Session provides access to the database
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+//Session provides access to the database. The Ctx variants of the methods stop when ctx is done.
+//Outside a transaction, the deadline of ctx is the timeout of the database transactions they run
 type Session interface {
 	Load(object interface{}, ID interface{}, loadOptions *LoadOptions) error
 	LoadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error
 	Reload(objects ...interface{}) error
 	Save(objects interface{}, saveOptions *SaveOptions) error
 	Delete(object interface{}) error
+	DeleteWithOptions(object interface{}, deleteOptions *DeleteOptions) error
 	DeleteAll(object interface{}, deleteOptions *DeleteOptions) error
+	Restore(object interface{}) error
+	//UpdateWhere sets properties on the entities of the type of object matching filter, and returns how many were updated.
+	//The versions of versioned entities are incremented without being checked, so concurrent changes aren't detected
+	UpdateWhere(object interface{}, filter *Filter, properties map[string]interface{}) (int64, error)
+	DeleteWhere(object interface{}, filter *Filter) (int64, error)
+	UpdateProperties(object interface{}, fieldNames ...string) error
+	UpdatePropertiesByID(object interface{}, ID interface{}, properties map[string]interface{}) error
 	PurgeDatabase() error
 	Clear() error
-	BeginTransaction() (*transaction, error)
-	GetTransaction() *transaction
+	BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (Transaction, error)
+	GetTransaction() Transaction
+	LastBookmark() string
+	ExecuteWrite(work TransactionWork) error
+	ExecuteRead(work TransactionWork) error
 	QueryForObject(object interface{}, cypher string, parameters map[string]interface{}) error
 	QueryForObjects(objects interface{}, cypher string, parameters map[string]interface{}) error
 	Query(cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error)
@@ -41,4 +59,25 @@ type Session interface {
 	Count(cypher string, parameters map[string]interface{}) (int64, error)
 	RegisterEventListener(EventListener) error
 	DisposeEventListener(EventListener) error
+	RegisterVetoingEventListener(VetoingEventListener) error
+	DisposeVetoingEventListener(VetoingEventListener) error
+
+	LoadCtx(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions) error
+	LoadAllCtx(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error
+	ReloadCtx(ctx context.Context, objects ...interface{}) error
+	SaveCtx(ctx context.Context, objects interface{}, saveOptions *SaveOptions) error
+	DeleteCtx(ctx context.Context, object interface{}) error
+	DeleteWithOptionsCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error
+	DeleteAllCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error
+	RestoreCtx(ctx context.Context, object interface{}) error
+	UpdateWhereCtx(ctx context.Context, object interface{}, filter *Filter, properties map[string]interface{}) (int64, error)
+	DeleteWhereCtx(ctx context.Context, object interface{}, filter *Filter) (int64, error)
+	UpdatePropertiesCtx(ctx context.Context, object interface{}, fieldNames ...string) error
+	UpdatePropertiesByIDCtx(ctx context.Context, object interface{}, ID interface{}, properties map[string]interface{}) error
+	PurgeDatabaseCtx(ctx context.Context) error
+	QueryForObjectCtx(ctx context.Context, object interface{}, cypher string, parameters map[string]interface{}) error
+	QueryForObjectsCtx(ctx context.Context, objects interface{}, cypher string, parameters map[string]interface{}) error
+	QueryCtx(ctx context.Context, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error)
+	CountEntitiesOfTypeCtx(ctx context.Context, object interface{}) (int64, error)
+	CountCtx(ctx context.Context, cypher string, parameters map[string]interface{}) (int64, error)
 }
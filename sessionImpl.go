@@ -23,6 +23,8 @@
 package gogm
 
 import (
+	"context"
+
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
@@ -31,42 +33,126 @@ type sessionImpl struct {
 	saver          *saver
 	loader         *loader
 	deleter        *deleter
+	updater        *updater
 	queryer        *queryer
 	transactioner  *transactioner
 	store          store
 	registry       *registry
-	driver         neo4j.Driver
+	backend        Backend
 	eventer        *eventer
 }
 
 func (s *sessionImpl) Load(object interface{}, ID interface{}, loadOptions *LoadOptions) error {
-	_, err := s.loader.load(object, ID, loadOptions, false)
+	return s.LoadCtx(context.Background(), object, ID, loadOptions)
+}
+
+func (s *sessionImpl) LoadCtx(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions) error {
+	_, err := s.loader.load(ctx, object, ID, loadOptions, false)
 	return err
 }
 
 func (s *sessionImpl) LoadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
-	return s.loader.loadAll(objects, IDs, loadOptions)
+	return s.LoadAllCtx(context.Background(), objects, IDs, loadOptions)
+}
+
+func (s *sessionImpl) LoadAllCtx(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
+	return s.loader.loadAll(ctx, objects, IDs, loadOptions)
 }
 
 func (s *sessionImpl) Reload(objects ...interface{}) error {
-	return s.loader.reload(objects...)
+	return s.ReloadCtx(context.Background(), objects...)
+}
+
+func (s *sessionImpl) ReloadCtx(ctx context.Context, objects ...interface{}) error {
+	return s.loader.reload(ctx, objects...)
 }
 
 func (s *sessionImpl) Save(objects interface{}, saveOptions *SaveOptions) error {
-	return s.saver.save(objects, saveOptions)
+	return s.SaveCtx(context.Background(), objects, saveOptions)
+}
+
+func (s *sessionImpl) SaveCtx(ctx context.Context, objects interface{}, saveOptions *SaveOptions) error {
+	if saveOptions != nil && saveOptions.BatchSize > 0 && !saveOptions.DryRun && s.transactioner.transaction == nil {
+		//A batched save runs several statements. Run them in a transaction of the access mode of the session, as unbatched
+		//saves are, so a failure doesn't leave the save half done
+		return s.transactioner.attemptTransaction(s, s.transactioner.accessMode, func(tx Session) error {
+			return s.saver.save(ctx, objects, saveOptions)
+		})
+	}
+	return s.saver.save(ctx, objects, saveOptions)
 }
 
 func (s *sessionImpl) Delete(object interface{}) error {
-	return s.deleter.delete(object)
+	return s.DeleteCtx(context.Background(), object)
+}
+
+func (s *sessionImpl) DeleteCtx(ctx context.Context, object interface{}) error {
+	return s.DeleteWithOptionsCtx(ctx, object, nil)
+}
+
+func (s *sessionImpl) DeleteWithOptions(object interface{}, deleteOptions *DeleteOptions) error {
+	return s.DeleteWithOptionsCtx(context.Background(), object, deleteOptions)
+}
+
+func (s *sessionImpl) DeleteWithOptionsCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
+	return s.deleter.delete(ctx, object, deleteOptions)
 }
 
 func (s *sessionImpl) DeleteAll(objects interface{}, deleteOptions *DeleteOptions) error {
-	return s.deleter.deleteAll(objects, deleteOptions)
+	return s.DeleteAllCtx(context.Background(), objects, deleteOptions)
+}
+
+func (s *sessionImpl) DeleteAllCtx(ctx context.Context, objects interface{}, deleteOptions *DeleteOptions) error {
+	return s.deleter.deleteAll(ctx, objects, deleteOptions)
+}
+
+func (s *sessionImpl) Restore(object interface{}) error {
+	return s.RestoreCtx(context.Background(), object)
+}
+
+func (s *sessionImpl) RestoreCtx(ctx context.Context, object interface{}) error {
+	return s.deleter.restore(ctx, object)
+}
+
+func (s *sessionImpl) UpdateWhere(object interface{}, filter *Filter, properties map[string]interface{}) (int64, error) {
+	return s.UpdateWhereCtx(context.Background(), object, filter, properties)
+}
+
+func (s *sessionImpl) UpdateWhereCtx(ctx context.Context, object interface{}, filter *Filter, properties map[string]interface{}) (int64, error) {
+	return s.updater.updateWhere(ctx, object, filter, properties)
+}
+
+func (s *sessionImpl) DeleteWhere(object interface{}, filter *Filter) (int64, error) {
+	return s.DeleteWhereCtx(context.Background(), object, filter)
+}
+
+func (s *sessionImpl) DeleteWhereCtx(ctx context.Context, object interface{}, filter *Filter) (int64, error) {
+	return s.deleter.deleteWhere(ctx, object, filter)
+}
+
+func (s *sessionImpl) UpdateProperties(object interface{}, fieldNames ...string) error {
+	return s.UpdatePropertiesCtx(context.Background(), object, fieldNames...)
+}
+
+func (s *sessionImpl) UpdatePropertiesCtx(ctx context.Context, object interface{}, fieldNames ...string) error {
+	return s.updater.updateProperties(ctx, object, fieldNames)
+}
+
+func (s *sessionImpl) UpdatePropertiesByID(object interface{}, ID interface{}, properties map[string]interface{}) error {
+	return s.UpdatePropertiesByIDCtx(context.Background(), object, ID, properties)
+}
+
+func (s *sessionImpl) UpdatePropertiesByIDCtx(ctx context.Context, object interface{}, ID interface{}, properties map[string]interface{}) error {
+	return s.updater.updatePropertiesByID(ctx, object, ID, properties)
 }
 
 func (s *sessionImpl) PurgeDatabase() error {
+	return s.PurgeDatabaseCtx(context.Background())
+}
+
+func (s *sessionImpl) PurgeDatabaseCtx(ctx context.Context) error {
 	var err error
-	if err = s.deleter.purgeDatabase(); err != nil {
+	if err = s.deleter.purgeDatabase(ctx); err != nil {
 		return err
 	}
 	return s.store.clear()
@@ -76,14 +162,29 @@ func (s *sessionImpl) Clear() error {
 	return s.store.clear()
 }
 
-func (s *sessionImpl) BeginTransaction() (*transaction, error) {
-	return s.transactioner.beginTransaction(s)
+func (s *sessionImpl) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (Transaction, error) {
+	return s.transactioner.beginTransaction(s, configurers...)
 }
 
-func (s *sessionImpl) GetTransaction() *transaction {
+func (s *sessionImpl) GetTransaction() Transaction {
+	if s.transactioner.transaction == nil {
+		return nil
+	}
 	return s.transactioner.transaction
 }
 
+func (s *sessionImpl) LastBookmark() string {
+	return s.cypherExecuter.lastBookmark()
+}
+
+func (s *sessionImpl) ExecuteWrite(work TransactionWork) error {
+	return s.transactioner.executeTransaction(s, neo4j.AccessModeWrite, work)
+}
+
+func (s *sessionImpl) ExecuteRead(work TransactionWork) error {
+	return s.transactioner.executeTransaction(s, neo4j.AccessModeRead, work)
+}
+
 //Precondition:
 // * object is a pointer to a pointer of domain object: **<domainObject>
 // * cypher returns one record with a column of domain object(s)
@@ -93,7 +194,11 @@ func (s *sessionImpl) GetTransaction() *transaction {
 //Post condition:
 //Polulated domain objects
 func (s *sessionImpl) QueryForObject(object interface{}, cypher string, parameters map[string]interface{}) error {
-	return s.queryer.queryForObject(object, cypher, parameters)
+	return s.QueryForObjectCtx(context.Background(), object, cypher, parameters)
+}
+
+func (s *sessionImpl) QueryForObjectCtx(ctx context.Context, object interface{}, cypher string, parameters map[string]interface{}) error {
+	return s.queryer.queryForObject(ctx, object, cypher, parameters)
 }
 
 //Precondition:
@@ -105,19 +210,35 @@ func (s *sessionImpl) QueryForObject(object interface{}, cypher string, paramete
 //Post condition:
 //Polulated domain objects
 func (s *sessionImpl) QueryForObjects(objects interface{}, cypher string, parameters map[string]interface{}) error {
-	return s.queryer.queryForObjects(objects, cypher, parameters)
+	return s.QueryForObjectsCtx(context.Background(), objects, cypher, parameters)
+}
+
+func (s *sessionImpl) QueryForObjectsCtx(ctx context.Context, objects interface{}, cypher string, parameters map[string]interface{}) error {
+	return s.queryer.queryForObjects(ctx, objects, cypher, parameters)
 }
 
 func (s *sessionImpl) Query(cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
-	return s.queryer.query(cypher, parameters, objects...)
+	return s.QueryCtx(context.Background(), cypher, parameters, objects...)
+}
+
+func (s *sessionImpl) QueryCtx(ctx context.Context, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
+	return s.queryer.query(ctx, cypher, parameters, objects...)
 }
 
 func (s *sessionImpl) CountEntitiesOfType(object interface{}) (int64, error) {
-	return s.queryer.countEntitiesOfType(object)
+	return s.CountEntitiesOfTypeCtx(context.Background(), object)
+}
+
+func (s *sessionImpl) CountEntitiesOfTypeCtx(ctx context.Context, object interface{}) (int64, error) {
+	return s.queryer.countEntitiesOfType(ctx, object)
 }
 
 func (s *sessionImpl) Count(cypher string, parameters map[string]interface{}) (int64, error) {
-	return s.queryer.count(cypher, parameters)
+	return s.CountCtx(context.Background(), cypher, parameters)
+}
+
+func (s *sessionImpl) CountCtx(ctx context.Context, cypher string, parameters map[string]interface{}) (int64, error) {
+	return s.queryer.count(ctx, cypher, parameters)
 }
 
 func (s *sessionImpl) RegisterEventListener(eventListener EventListener) error {
@@ -126,3 +247,11 @@ func (s *sessionImpl) RegisterEventListener(eventListener EventListener) error {
 func (s *sessionImpl) DisposeEventListener(eventListener EventListener) error {
 	return s.eventer.disposeEventListener(eventListener)
 }
+
+func (s *sessionImpl) RegisterVetoingEventListener(eventListener VetoingEventListener) error {
+	return s.eventer.registerVetoingEventListener(eventListener)
+}
+
+func (s *sessionImpl) DisposeVetoingEventListener(eventListener VetoingEventListener) error {
+	return s.eventer.disposeVetoingEventListener(eventListener)
+}
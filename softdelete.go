@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var typeOfPtrToTime = reflect.TypeOf(&time.Time{})
+
+func getSoftDeleteBackendName(structFields map[string]*reflect.StructField) (string, error) {
+	var softDeleteBackendName string
+	for backendName, structField := range structFields {
+		if len(getNamespacedTag(structField.Tag).get(softDeleteTag)) > 0 {
+			if softDeleteBackendName != emptyString {
+				return emptyString, errors.New("Only one field can be tagged 'softdelete'")
+			}
+			if structField.Type.Kind() != reflect.Bool && structField.Type != typeOfPtrToTime {
+				return emptyString, errors.New("Invalid soft delete type. Soft delete type must be a bool or a *time.Time")
+			}
+			softDeleteBackendName = backendName
+		}
+	}
+	return softDeleteBackendName, nil
+}
+
+//getSoftDeletedPredicate returns the predicate telling whether the entity referenced by ref is soft deleted,
+//or an empty string when entities of metadata aren't soft deleted
+func getSoftDeletedPredicate(ref string, metadata metadata) string {
+	propertyName := metadata.getSoftDeleteBackendName()
+	if propertyName == emptyString {
+		return emptyString
+	}
+	if metadata.getPropertyStructFields()[propertyName].Type.Kind() == reflect.Bool {
+		return `coalesce(` + ref + `.` + propertyName + `, false) = true`
+	}
+	return ref + `.` + propertyName + ` IS NOT NULL`
+}
+
+//getNotSoftDeletedPredicate returns the predicate telling whether the entity referenced by ref isn't soft deleted
+func getNotSoftDeletedPredicate(ref string, metadata metadata) string {
+	if predicate := getSoftDeletedPredicate(ref, metadata); predicate != emptyString {
+		return `NOT ` + predicate
+	}
+	return emptyString
+}
+
+//getSoftDeleteValue returns the value of the soft delete property of entities of metadata soft deleted at deletedAt
+func getSoftDeleteValue(metadata metadata, deletedAt time.Time) interface{} {
+	if metadata.getPropertyStructFields()[metadata.getSoftDeleteBackendName()].Type.Kind() == reflect.Bool {
+		return true
+	}
+	return deletedAt
+}
+
+//getRestoreValue returns the value of the soft delete property of restored entities of metadata
+func getRestoreValue(metadata metadata) interface{} {
+	if metadata.getPropertyStructFields()[metadata.getSoftDeleteBackendName()].Type.Kind() == reflect.Bool {
+		return false
+	}
+	return nil
+}
+
+//unloadGraphSoftDelete sets the soft delete field of the domain object of g, and its property, to value
+func unloadGraphSoftDelete(g graph, metadata metadata, value interface{}) {
+	propertyName := metadata.getSoftDeleteBackendName()
+	if propertyName == emptyString || g.getValue() == nil || !g.getValue().IsValid() {
+		return
+	}
+	structField := metadata.getPropertyStructFields()[propertyName]
+	fieldValue := reflect.Zero(structField.Type)
+	if t, isTime := value.(time.Time); isTime {
+		fieldValue = reflect.ValueOf(&t)
+	} else if value != nil {
+		fieldValue = reflect.ValueOf(value)
+	}
+	g.getValue().Elem().FieldByName(structField.Name).Set(fieldValue)
+	if properties := g.getProperties(); properties != nil {
+		properties[propertyName] = fieldValue.Interface()
+	}
+}
+
+//getSoftDeletedPathPredicate returns the predicate excluding paths through soft deleted entities of the types
+//reachable from the entities of metadata
+func getSoftDeletedPathPredicate(path string, metadata metadata, registry *registry) (string, error) {
+	var (
+		nodePredicates         []string
+		relationshipPredicates []string
+		predicates             []string
+	)
+	metadatas, err := getReachableMetadatas(metadata, registry)
+	if err != nil {
+		return emptyString, err
+	}
+	for _, reachableMetadata := range metadatas {
+		switch reachableMetadata.(type) {
+		case *nodeMetadata:
+			if predicate := getSoftDeletedPredicate("x", reachableMetadata); predicate != emptyString {
+				nodePredicates = append(nodePredicates, `(x:`+reachableMetadata.getStructLabel()+` AND `+predicate+`)`)
+			}
+		case *relationshipMetadata:
+			if predicate := getSoftDeletedPredicate("x", reachableMetadata); predicate != emptyString {
+				relationshipPredicates = append(relationshipPredicates, `(type(x) = '`+reachableMetadata.getStructLabel()+`' AND `+predicate+`)`)
+			}
+		}
+	}
+	if len(nodePredicates) > 0 {
+		predicates = append(predicates, `none(x IN nodes(`+path+`) WHERE `+strings.Join(nodePredicates, ` OR `)+`)`)
+	}
+	if len(relationshipPredicates) > 0 {
+		predicates = append(predicates, `none(x IN relationships(`+path+`) WHERE `+strings.Join(relationshipPredicates, ` OR `)+`)`)
+	}
+	return strings.Join(predicates, booleanOperators[andOperator]), nil
+}
+
+//getReachableMetadatas returns rootMetadata and the metadata of the entities related to its entities, at any depth
+func getReachableMetadatas(rootMetadata metadata, registry *registry) ([]metadata, error) {
+	var (
+		reachableMetadatas []metadata
+		visited            = map[reflect.Type]bool{rootMetadata.getType(): true}
+		queue              = []metadata{rootMetadata}
+	)
+	for len(queue) > 0 {
+		var relatedTypes []reflect.Type
+		switch m := queue[0].(type) {
+		case *nodeMetadata:
+			for _, structField := range m.relationshipAStructFields {
+				relatedTypes = append(relatedTypes, elem(structField.Type))
+			}
+			for _, structField := range m.relationshipBStructFields {
+				relatedTypes = append(relatedTypes, elem(structField.Type))
+			}
+		case *relationshipMetadata:
+			relatedTypes = append(relatedTypes, m.endpoints[startNode].Type, m.endpoints[endNode].Type)
+		}
+		reachableMetadatas = append(reachableMetadatas, queue[0])
+		queue = queue[1:]
+
+		for _, relatedType := range relatedTypes {
+			if visited[relatedType] {
+				continue
+			}
+			visited[relatedType] = true
+			relatedMetadata, err := registry.get(relatedType)
+			if err != nil {
+				return nil, err
+			}
+			queue = append(queue, relatedMetadata)
+		}
+	}
+	return reachableMetadatas, nil
+}
@@ -58,10 +58,32 @@ type store interface {
 	// getByCustomID returns the graph with whose custom ID is interface{}
 	getByCustomID(reflect.Value, reflect.Type, interface{}) graph
 
+	// snapshot returns the current state of the store and its graphs
+	snapshot() *storeSnapshot
+
+	// restore resets the store, its graphs and the IDs of their domain objects to a snapshot
+	restore(*storeSnapshot)
+
 	// // print prints the store
 	// print()
 }
 
+//storeSnapshot is the state of a store and of the graphs it holds at a point in time
+type storeSnapshot struct {
+	nodes          map[int64]graph
+	relationships  map[int64]graph
+	relationshipsA map[int64]map[int64]*int64
+	customIDs      map[string]map[interface{}]*int64
+	graphs         map[graph]graphSnapshot
+}
+
+type graphSnapshot struct {
+	ID            int64
+	depth         *int
+	properties    map[string]interface{}
+	relatedGraphs map[int64]graph
+}
+
 type storeImpl struct {
 	registry *registry
 
@@ -244,6 +266,87 @@ func (s *storeImpl) getByCustomID(v reflect.Value, typeOfRefGraph reflect.Type,
 	return nil
 }
 
+func (s *storeImpl) snapshot() *storeSnapshot {
+	s.nodesMu.Lock()
+	defer s.nodesMu.Unlock()
+
+	snapshot := &storeSnapshot{
+		nodes:          make(map[int64]graph, len(s.nodes)),
+		relationships:  make(map[int64]graph, len(s.relationships)),
+		relationshipsA: make(map[int64]map[int64]*int64, len(s.relationshipsA)),
+		customIDs:      make(map[string]map[interface{}]*int64, len(s.customIDs)),
+		graphs:         make(map[graph]graphSnapshot, len(s.nodes)+len(s.relationships))}
+
+	for ID, node := range s.nodes {
+		snapshot.nodes[ID] = node
+		snapshot.graphs[node] = newGraphSnapshot(node)
+	}
+	for ID, relationship := range s.relationships {
+		snapshot.relationships[ID] = relationship
+		snapshot.graphs[relationship] = newGraphSnapshot(relationship)
+	}
+	for startID, endIDs := range s.relationshipsA {
+		snapshot.relationshipsA[startID] = make(map[int64]*int64, len(endIDs))
+		for endID, ID := range endIDs {
+			snapshot.relationshipsA[startID][endID] = ID
+		}
+	}
+	for typeName, IDs := range s.customIDs {
+		snapshot.customIDs[typeName] = make(map[interface{}]*int64, len(IDs))
+		for customID, ID := range IDs {
+			snapshot.customIDs[typeName][customID] = ID
+		}
+	}
+	return snapshot
+}
+
+func (s *storeImpl) restore(snapshot *storeSnapshot) {
+	s.nodesMu.Lock()
+	defer s.nodesMu.Unlock()
+
+	s.nodes = snapshot.nodes
+	s.relationships = snapshot.relationships
+	s.relationshipsA = snapshot.relationshipsA
+	s.customIDs = snapshot.customIDs
+
+	for g, graphSnapshot := range snapshot.graphs {
+		g.setID(graphSnapshot.ID)
+		g.setDepth(graphSnapshot.depth)
+		g.setProperties(graphSnapshot.properties)
+
+		relatedGraphs := g.getRelatedGraphs()
+		for ID := range relatedGraphs {
+			delete(relatedGraphs, ID)
+		}
+		for ID, relatedGraph := range graphSnapshot.relatedGraphs {
+			relatedGraphs[ID] = relatedGraph
+		}
+
+		if IDAddr := getIDAddr(g); IDAddr != nil {
+			ID := graphSnapshot.ID
+			*IDAddr = &ID
+		}
+	}
+}
+
+func newGraphSnapshot(g graph) graphSnapshot {
+	graphSnapshot := graphSnapshot{
+		ID:            g.getID(),
+		depth:         g.getDepth(),
+		relatedGraphs: make(map[int64]graph, len(g.getRelatedGraphs()))}
+
+	if g.getProperties() != nil {
+		graphSnapshot.properties = make(map[string]interface{}, len(g.getProperties()))
+		for name, value := range g.getProperties() {
+			graphSnapshot.properties[name] = value
+		}
+	}
+	for ID, relatedGraph := range g.getRelatedGraphs() {
+		graphSnapshot.relatedGraphs[ID] = relatedGraph
+	}
+	return graphSnapshot
+}
+
 func unwind(g graph, depth int) store {
 	visited := newstore(nil)
 	maxDepth := depth * 2
@@ -37,6 +37,10 @@ var (
 	propertyNameTag = "name"
 	uniqueTag       = "unique"
 	indexTag        = "index"
+	versionTag      = "version"
+	mergeTag        = "merge"
+	cascadeTag      = "cascade"
+	softDeleteTag   = "softdelete"
 )
 
 var (
@@ -92,3 +92,57 @@ func (e *TestEventListener) OnPostDelete(event gogm.Event) {
 		}
 	}
 }
+
+//TestTransactionListener records the notices it receives
+type TestTransactionListener struct {
+	Notices []string
+}
+
+func (e *TestTransactionListener) OnPreSave(event gogm.Event) {}
+
+func (e *TestTransactionListener) OnPostSave(event gogm.Event) {
+	e.Notices = append(e.Notices, "OnPostSave")
+}
+
+func (e *TestTransactionListener) OnPostLoad(event gogm.Event) {}
+
+func (e *TestTransactionListener) OnPreDelete(event gogm.Event) {}
+
+func (e *TestTransactionListener) OnPostDelete(event gogm.Event) {
+	e.Notices = append(e.Notices, "OnPostDelete")
+}
+
+func (e *TestTransactionListener) OnBeforeCommit() {
+	e.Notices = append(e.Notices, "OnBeforeCommit")
+}
+
+func (e *TestTransactionListener) OnAfterCommit() {
+	e.Notices = append(e.Notices, "OnAfterCommit")
+}
+
+func (e *TestTransactionListener) OnAfterRollback() {
+	e.Notices = append(e.Notices, "OnAfterRollback")
+}
+
+//TestVetoingEventListener vetoes saves and deletes with Err. PreDelete, when set, is called before deletes are vetoed
+type TestVetoingEventListener struct {
+	Err       error
+	PreDelete func(event gogm.Event)
+}
+
+func (e *TestVetoingEventListener) OnPreSave(event gogm.Event) error {
+	return e.Err
+}
+
+func (e *TestVetoingEventListener) OnPostSave(event gogm.Event) {}
+
+func (e *TestVetoingEventListener) OnPostLoad(event gogm.Event) {}
+
+func (e *TestVetoingEventListener) OnPreDelete(event gogm.Event) error {
+	if e.PreDelete != nil {
+		e.PreDelete(event)
+	}
+	return e.Err
+}
+
+func (e *TestVetoingEventListener) OnPostDelete(event gogm.Event) {}
@@ -23,6 +23,8 @@
 package models
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	gogm "github.com/codingfinest/neo4j-go-ogm"
@@ -135,3 +137,83 @@ type InvalidID struct {
 	TestNodeEntity
 	TestId *string `gogm:"id,name:IDs"`
 }
+
+type CallbackNode struct {
+	TestNodeEntity
+	Name      string
+	Callbacks []string `gogm:"-"`
+}
+
+func (n *CallbackNode) BeforeSave() error {
+	n.Callbacks = append(n.Callbacks, "BeforeSave")
+	if n.Name == "" {
+		return errors.New("name is required")
+	}
+	n.Name = strings.TrimSpace(n.Name)
+	return nil
+}
+
+func (n *CallbackNode) AfterSave() {
+	n.Callbacks = append(n.Callbacks, "AfterSave")
+}
+
+func (n *CallbackNode) AfterLoad() {
+	n.Callbacks = append(n.Callbacks, "AfterLoad")
+}
+
+func (n *CallbackNode) BeforeDelete() error {
+	n.Callbacks = append(n.Callbacks, "BeforeDelete")
+	return nil
+}
+
+type VersionedNode struct {
+	TestNodeEntity
+	Name    string
+	Version int64 `gogm:"version"`
+	Knows   *VersionedRelationship
+}
+
+type AmbiguousMergeNode struct {
+	TestNodeEntity
+	Email string `gogm:"unique"`
+	Login string `gogm:"unique"`
+}
+
+type VersionedMergeNode struct {
+	TestNodeEntity
+	Code    string `gogm:"unique"`
+	Name    string
+	Version int64 `gogm:"version"`
+}
+
+type MergeNode struct {
+	TestNodeEntity
+	Name    string
+	Follows []*SimpleNode `gogm:"merge"`
+	Rates   []*Rating
+}
+
+type CascadeOwner struct {
+	TestNodeEntity
+	Name  string
+	Parts []*CascadePart `gogm:"cascade:delete"`
+}
+
+type CascadePart struct {
+	TestNodeEntity
+	Name   string
+	Pieces []*SimpleNode `gogm:"cascade:delete"`
+}
+
+type SoftDeleteNode struct {
+	TestNodeEntity
+	Name      string
+	RemovedAt *time.Time `gogm:"softdelete"`
+	Children  []*SoftDeleteChild
+}
+
+type SoftDeleteChild struct {
+	TestNodeEntity
+	Name    string
+	Deleted bool `gogm:"softdelete"`
+}
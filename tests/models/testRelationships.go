@@ -29,6 +29,11 @@ type TestRelationshipEntity struct {
 	TestEntity
 }
 
+type MergedRelationshipEntity struct {
+	gogm.Relationship `gogm:"merge"`
+	TestEntity
+}
+
 //Relationships
 
 type SimpleRelationship struct {
@@ -59,3 +64,25 @@ type SimpleRelationshipSameNode struct {
 	N52  *Node5 `gogm:"endNode"`
 	Name string
 }
+
+type VersionedRelationship struct {
+	TestRelationshipEntity
+	From    *VersionedNode `gogm:"startNode"`
+	To      *VersionedNode `gogm:"endNode"`
+	Since   int64
+	Version int `gogm:"version"`
+}
+
+type Rating struct {
+	TestRelationshipEntity `gogm:"merge"`
+	From                   *MergeNode  `gogm:"startNode"`
+	To                     *SimpleNode `gogm:"endNode"`
+	Source                 string      `gogm:"merge"`
+	Stars                  int64
+}
+
+type Endorsement struct {
+	MergedRelationshipEntity
+	From *MergeNode  `gogm:"startNode"`
+	To   *SimpleNode `gogm:"endNode"`
+}
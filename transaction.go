@@ -23,35 +23,92 @@
 package gogm
 
 import (
+	"errors"
+	"time"
+
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
+//Transaction is an explicit database transaction of a session, begun with Session.BeginTransaction. Statements the
+//session runs while the transaction is open are part of it
+type Transaction interface {
+	//Commit commits the changes made in the transaction
+	Commit() error
+
+	//Rollback discards the changes made in the transaction, and restores the session cache
+	Rollback() error
+
+	//Close ends the transaction. Changes that weren't committed are discarded
+	Close() error
+
+	//IsOpen tells whether statements can still run in the transaction
+	IsOpen() bool
+
+	//Timeout is the timeout of the transaction set at begin time. Zero means the server default
+	Timeout() time.Duration
+
+	//Metadata is the metadata attached to the transaction at begin time
+	Metadata() map[string]interface{}
+}
+
+//ErrRollbackOnly is returned when committing a transaction after one of its nested transactions was rolled back.
+//The transaction is rolled back instead
+var ErrRollbackOnly = errors.New("Transaction was rolled back. A nested transaction was rolled back")
+
 type transaction struct {
 	neo4jTransaction neo4j.Transaction
 	session          neo4j.Session
 	close            transactionEnder
+	config           neo4j.TransactionConfig
+	closed           bool
+
+	//store is restored to storeSnapshot and the IDs of createdGraphs are reset when the transaction isn't committed
+	store         store
+	storeSnapshot *storeSnapshot
+	createdGraphs []graph
+	committed     bool
+	discarded     bool
+
+	//rollbackOnly is set by rolled back nested transactions. openNestedTransactions are the nested transactions
+	//neither committed nor rolled back
+	rollbackOnly           bool
+	openNestedTransactions int
+
+	//notices are the post save and post delete events buffered till the transaction is committed
+	eventer *eventer
+	notices []func()
 }
 
-func newTransaction(driver neo4j.Driver, transactionEnder transactionEnder, accessMode neo4j.AccessMode) (*transaction, error) {
+func newTransaction(backend Backend, transactionEnder transactionEnder, accessMode neo4j.AccessMode, database string, bookmarks []string, store store, eventer *eventer, configurers ...func(*neo4j.TransactionConfig)) (*transaction, error) {
 
 	var (
 		err     error
 		session neo4j.Session
+		config  = neo4j.TransactionConfig{}
 	)
 
-	if session, err = driver.Session(accessMode); err != nil {
+	for _, configurer := range configurers {
+		configurer(&config)
+	}
+
+	if session, err = newDriverSession(backend, accessMode, database, bookmarks...); err != nil {
 		return nil, err
 	}
 
 	var neo4jtransaction neo4j.Transaction
-	if neo4jtransaction, err = session.BeginTransaction(); err != nil {
+	if neo4jtransaction, err = session.BeginTransaction(configurers...); err != nil {
+		session.Close()
 		return nil, err
 	}
 
 	return &transaction{
 		neo4jTransaction: neo4jtransaction,
 		session:          session,
-		close:            transactionEnder}, nil
+		close:            transactionEnder,
+		config:           config,
+		store:            store,
+		storeSnapshot:    store.snapshot(),
+		eventer:          eventer}, nil
 }
 
 func (t *transaction) run(cql string, params map[string]interface{}) (neo4j.Result, error) {
@@ -59,13 +116,159 @@ func (t *transaction) run(cql string, params map[string]interface{}) (neo4j.Resu
 }
 
 func (t *transaction) Commit() error {
-	return t.neo4jTransaction.Commit()
+	if t.openNestedTransactions > 0 {
+		return errors.New("Nested transactions must be committed or rolled back before their outer transaction")
+	}
+	if t.rollbackOnly {
+		if err := t.Rollback(); err != nil {
+			return err
+		}
+		return ErrRollbackOnly
+	}
+
+	transactionListeners := t.eventer.transactionListeners()
+	for _, transactionListener := range transactionListeners {
+		transactionListener.OnBeforeCommit()
+	}
+
+	if err := t.neo4jTransaction.Commit(); err != nil {
+		return err
+	}
+	t.committed = true
+
+	notices := t.notices
+	t.notices = nil
+	for _, notice := range notices {
+		notice()
+	}
+	for _, transactionListener := range transactionListeners {
+		transactionListener.OnAfterCommit()
+	}
+	return nil
 }
 
-func (t *transaction) RollBack() error {
-	return t.neo4jTransaction.Rollback()
+func (t *transaction) Rollback() error {
+	err := t.neo4jTransaction.Rollback()
+	t.discardChanges()
+	return err
 }
 
 func (t *transaction) Close() error {
+	if t.closed {
+		return nil
+	}
+	if !t.committed {
+		t.discardChanges()
+	}
+	t.closed = true
 	return t.close()
 }
+
+func (t *transaction) IsOpen() bool {
+	return !t.committed && !t.discarded && !t.closed
+}
+
+func (t *transaction) Timeout() time.Duration {
+	return t.config.Timeout
+}
+
+func (t *transaction) Metadata() map[string]interface{} {
+	return t.config.Metadata
+}
+
+func (t *transaction) bufferNotice(notice func()) {
+	t.notices = append(t.notices, notice)
+}
+
+func (t *transaction) addCreatedGraph(g graph) {
+	t.createdGraphs = append(t.createdGraphs, g)
+}
+
+func (t *transaction) discardChanges() {
+	if t.discarded {
+		return
+	}
+	t.discarded = true
+
+	for _, createdGraph := range t.createdGraphs {
+		unloadGraphID(createdGraph, nil)
+	}
+	t.store.restore(t.storeSnapshot)
+
+	t.notices = nil
+	for _, transactionListener := range t.eventer.transactionListeners() {
+		transactionListener.OnAfterRollback()
+	}
+}
+
+//nestedTransaction is a transaction begun while the session already has a transaction. It joins the statements of
+//the outer transaction, and saves the session cache at its start like a savepoint. Committing it commits nothing,
+//its changes are committed with the outer transaction. Rolling it back restores the session cache to the savepoint
+//and marks the outer transaction rollback only: committing the outer transaction rolls it back and returns ErrRollbackOnly
+type nestedTransaction struct {
+	outer         *transaction
+	storeSnapshot *storeSnapshot
+	createdGraphs int
+	notices       int
+	done          bool
+}
+
+func newNestedTransaction(outer *transaction) *nestedTransaction {
+	outer.openNestedTransactions++
+	return &nestedTransaction{
+		outer:         outer,
+		storeSnapshot: outer.store.snapshot(),
+		createdGraphs: len(outer.createdGraphs),
+		notices:       len(outer.notices)}
+}
+
+func (n *nestedTransaction) Commit() error {
+	if n.done {
+		return errors.New("Transaction is already committed or rolled back")
+	}
+	n.end()
+	return nil
+}
+
+func (n *nestedTransaction) Rollback() error {
+	if n.done {
+		return errors.New("Transaction is already committed or rolled back")
+	}
+	n.end()
+	n.outer.rollbackOnly = true
+
+	if n.outer.discarded {
+		return nil
+	}
+	for _, createdGraph := range n.outer.createdGraphs[n.createdGraphs:] {
+		unloadGraphID(createdGraph, nil)
+	}
+	n.outer.createdGraphs = n.outer.createdGraphs[:n.createdGraphs]
+	n.outer.notices = n.outer.notices[:n.notices]
+	n.outer.store.restore(n.storeSnapshot)
+	return nil
+}
+
+func (n *nestedTransaction) Close() error {
+	if !n.done {
+		return n.Rollback()
+	}
+	return nil
+}
+
+func (n *nestedTransaction) IsOpen() bool {
+	return !n.done && n.outer.IsOpen()
+}
+
+func (n *nestedTransaction) Timeout() time.Duration {
+	return n.outer.Timeout()
+}
+
+func (n *nestedTransaction) Metadata() map[string]interface{} {
+	return n.outer.Metadata()
+}
+
+func (n *nestedTransaction) end() {
+	n.done = true
+	n.outer.openNestedTransactions--
+}
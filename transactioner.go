@@ -24,12 +24,25 @@ package gogm
 
 import (
 	"errors"
+	"math/rand"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
+const (
+	maxTransactionRetryTime      = 30 * time.Second
+	initialTransactionRetryDelay = time.Second
+	transactionRetryDelayFactor  = 2.0
+	transactionRetryJitter       = 0.2
+)
+
 type transactionEnder func() error
 
+//TransactionWork is the unit of work run by Session.ExecuteWrite and Session.ExecuteRead.
+//tx is the session the work runs in. Work may be run more than once and should be idempotent
+type TransactionWork func(tx Session) error
+
 type transactioner struct {
 	transaction *transaction
 	accessMode  neo4j.AccessMode
@@ -39,13 +52,22 @@ func newTransactioner(accessMode neo4j.AccessMode) *transactioner {
 	return &transactioner{accessMode: accessMode}
 }
 
-func (t *transactioner) beginTransaction(s *sessionImpl) (*transaction, error) {
+func (t *transactioner) beginTransaction(s *sessionImpl, configurers ...func(*neo4j.TransactionConfig)) (Transaction, error) {
+	return t.beginTransactionWithAccessMode(s, t.accessMode, configurers...)
+}
+
+//beginTransactionWithAccessMode begins a transaction, or a nested transaction of the transaction of the session.
+//Nested transactions have the access mode and the configuration of their outer transaction
+func (t *transactioner) beginTransactionWithAccessMode(s *sessionImpl, accessMode neo4j.AccessMode, configurers ...func(*neo4j.TransactionConfig)) (Transaction, error) {
 	if t.transaction != nil {
-		return nil, errors.New("Transaction already exists")
+		if !t.transaction.IsOpen() {
+			return nil, errors.New("Transaction is already committed or rolled back")
+		}
+		return newNestedTransaction(t.transaction), nil
 	}
 
 	var err error
-	if t.transaction, err = newTransaction(s.driver, t.endTransaction(s), t.accessMode); err != nil {
+	if t.transaction, err = newTransaction(s.backend, t.endTransaction(s), accessMode, s.cypherExecuter.database, s.cypherExecuter.bookmarks, s.store, s.eventer, configurers...); err != nil {
 		return nil, err
 	}
 
@@ -64,6 +86,7 @@ func (t *transactioner) endTransaction(s *sessionImpl) func() error {
 		if err = t.transaction.neo4jTransaction.Close(); err != nil {
 			return err
 		}
+		s.cypherExecuter.setLastBookmark(t.transaction.session)
 
 		if err = t.transaction.session.Close(); err != nil {
 			return err
@@ -75,3 +98,65 @@ func (t *transactioner) endTransaction(s *sessionImpl) func() error {
 		return nil
 	}
 }
+
+//executeTransaction runs work in a transaction that is committed when work succeeds and rolled back when it fails or panics.
+//Work failing with a retryable error is run again in a new transaction, with an exponential backoff, until maxTransactionRetryTime elapses.
+func (t *transactioner) executeTransaction(s *sessionImpl, accessMode neo4j.AccessMode, work TransactionWork) error {
+	var (
+		err       error
+		startTime = time.Now()
+		delay     = initialTransactionRetryDelay
+	)
+
+	//Work nested in a transaction isn't retried, the outer transaction is rollback only when it fails
+	if t.transaction != nil {
+		return t.attemptTransaction(s, accessMode, work)
+	}
+
+	for {
+		if err = t.attemptTransaction(s, accessMode, work); err == nil {
+			return nil
+		}
+		if !isRetryableError(err) || time.Since(startTime) >= maxTransactionRetryTime {
+			return err
+		}
+		time.Sleep(jitter(delay))
+		delay = time.Duration(float64(delay) * transactionRetryDelayFactor)
+	}
+}
+
+func (t *transactioner) attemptTransaction(s *sessionImpl, accessMode neo4j.AccessMode, work TransactionWork) (err error) {
+	var tx Transaction
+	if tx, err = t.beginTransactionWithAccessMode(s, accessMode); err != nil {
+		return err
+	}
+
+	defer func() {
+		if tx.IsOpen() {
+			tx.Rollback()
+		}
+		if closeErr := tx.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	if err = work(s); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func isRetryableError(err error) bool {
+	if neo4j.IsTransientError(err) || neo4j.IsSessionExpired(err) || neo4j.IsServiceUnavailable(err) {
+		return true
+	}
+	if databaseError, isDatabaseError := err.(interface{ Code() string }); isDatabaseError {
+		return databaseError.Code() == "Neo.ClientError.Cluster.NotALeader" ||
+			databaseError.Code() == "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"
+	}
+	return false
+}
+
+func jitter(delay time.Duration) time.Duration {
+	return time.Duration(float64(delay) * (1 - transactionRetryJitter + 2*transactionRetryJitter*rand.Float64()))
+}
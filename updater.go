@@ -0,0 +1,346 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type updater struct {
+	cypherExecuter *cypherExecuter
+	store          store
+	eventer        eventer
+	registry       *registry
+	graphFactory   graphFactory
+}
+
+func newUpdater(cypherExecuter *cypherExecuter, store store, eventer eventer, registry *registry, graphFactory graphFactory) *updater {
+	return &updater{cypherExecuter, store, eventer, registry, graphFactory}
+}
+
+//updateWhere sets properties on the entities of the type of object matching filter, and returns how many were updated
+func (u *updater) updateWhere(ctx context.Context, object interface{}, filter *Filter, properties map[string]interface{}) (int64, error) {
+	var (
+		graphs   []graph
+		metadata metadata
+		records  []neo4j.Record
+		err      error
+	)
+
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if graphs, err = u.graphFactory.get(reflect.ValueOf(object), map[int]bool{labels: true}); err != nil {
+		return 0, err
+	}
+	if metadata, err = u.registry.get(graphs[0].getValue().Type()); err != nil {
+		return 0, err
+	}
+	if err = validateUpdatedProperties(metadata, properties); err != nil {
+		return 0, err
+	}
+
+	ref, match, parameters, err := getWhereMatch(graphs[0], metadata, u.registry, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if isSaveVetoable(u.eventer, metadata) {
+		var matchedGraphs []graph
+		if matchedGraphs, err = getMatchedGraphs(ctx, u.cypherExecuter, u.store, metadata, match+`RETURN `+ref, parameters); err != nil {
+			return 0, err
+		}
+		for _, matchedGraph := range matchedGraphs {
+			if err = notifyPreSave(u.eventer, matchedGraph, UPDATE); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	set := `SET ` + ref + ` += $properties
+	`
+	parameters["properties"] = properties
+	if versionPropertyName, _ := metadata.getVersion(reflect.New(metadata.getType().Elem())); versionPropertyName != emptyString {
+		set += `SET ` + ref + `.` + versionPropertyName + ` = coalesce(` + ref + `.` + versionPropertyName + `, 0) + 1
+	`
+	}
+
+	if records, err = neo4j.Collect(u.cypherExecuter.execContext(ctx, match+set+`RETURN `+ref, parameters)); err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		var stored graph
+		switch entity := record.GetByIndex(0).(type) {
+		case neo4j.Node:
+			if stored = u.store.node(entity.Id()); stored != nil {
+				u.updateStored(stored, metadata, entity.Props(), properties)
+			}
+		case neo4j.Relationship:
+			if stored = u.store.relationship(entity.Id()); stored != nil {
+				u.updateStored(stored, metadata, entity.Props(), properties)
+			}
+		}
+		if stored != nil {
+			notifyPostSave(u.eventer, u.cypherExecuter.transaction, stored, UPDATE)
+		}
+	}
+
+	return int64(len(records)), nil
+}
+
+//updateStored sets the properties updated, and the fields of the domain object of stored, to the database properties
+func (u *updater) updateStored(stored graph, metadata metadata, databaseProperties map[string]interface{}, updated map[string]interface{}) {
+	var (
+		properties           = map[string]interface{}{}
+		updatedStructFields  = map[string]*reflect.StructField{}
+		propertyStructFields = metadata.getPropertyStructFields()
+	)
+	for propertyName, propertyValue := range databaseProperties {
+		properties[propertyName] = propertyValue
+	}
+	driverPropertiesAsStructFieldValues(properties, propertyStructFields)
+	versionPropertyName, _ := metadata.getVersion(reflect.New(metadata.getType().Elem()))
+	for propertyName, structField := range propertyStructFields {
+		if _, isUpdated := updated[propertyName]; isUpdated || propertyName == versionPropertyName {
+			stored.getProperties()[propertyName] = properties[propertyName]
+			updatedStructFields[propertyName] = structField
+		}
+	}
+	unloadGraphProperties(stored, updatedStructFields)
+}
+
+//validateUpdatedProperties checks properties are backend property names of entities of metadata
+func validateUpdatedProperties(metadata metadata, properties map[string]interface{}) error {
+	if len(properties) == 0 {
+		return errors.New("No properties to update")
+	}
+	for propertyName := range properties {
+		if metadata.getPropertyStructFields()[propertyName] == nil || propertyName == idPropertyName {
+			return errors.New("Unknown property '" + propertyName + "' on domain object '" + metadata.getType().String() + "'")
+		}
+	}
+	return nil
+}
+
+//getWhereMatch returns the reference and the MATCH clause of the entities of the type of g matching filter.
+//Soft deleted entities aren't matched
+func getWhereMatch(g graph, metadata metadata, registry *registry, filter *Filter) (string, string, map[string]interface{}, error) {
+	var (
+		ref        = "n"
+		match      = `MATCH (n:` + g.getLabel() + `)`
+		predicates []string
+		parameters = map[string]interface{}{}
+	)
+	if typeOfPrivateRelationship == reflect.TypeOf(g) {
+		ref = "r"
+		match = `MATCH ()-[r:` + g.getLabel() + `]->()`
+	}
+	if filter != nil {
+		predicate, err := filter.getCypher(ref, metadata, registry, 0, parameters)
+		if err != nil {
+			return emptyString, emptyString, nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	if predicate := getNotSoftDeletedPredicate(ref, metadata); predicate != emptyString {
+		predicates = append(predicates, predicate)
+	}
+	if len(predicates) > 0 {
+		match += `
+	WHERE ` + strings.Join(predicates, booleanOperators[andOperator])
+	}
+	return ref, match + `
+	`, parameters, nil
+}
+
+//updateProperties writes the properties of the fields fieldNames of object to the database
+func (u *updater) updateProperties(ctx context.Context, object interface{}, fieldNames []string) error {
+	var (
+		graphs            []graph
+		metadata          metadata
+		updatedProperties = map[string]interface{}{}
+		IDer              = getIDer(nil, nil)
+		err               error
+	)
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if graphs, err = u.graphFactory.get(reflect.ValueOf(object), map[int]bool{labels: true, properties: true}); err != nil {
+		return err
+	}
+	IDer(graphs[0])
+	if metadata, err = u.registry.get(graphs[0].getValue().Type()); err != nil {
+		return err
+	}
+	if err = notifyPreSaveGraph(graphs[0], u.eventer, u.registry); err != nil {
+		return err
+	}
+
+	for _, fieldName := range fieldNames {
+		backendName := emptyString
+		for propertyName, structField := range metadata.getPropertyStructFields() {
+			if structField.Name == fieldName {
+				backendName = propertyName
+			}
+		}
+		if backendName == emptyString || backendName == idPropertyName {
+			return errors.New("Unknown property field '" + fieldName + "' on domain object '" + metadata.getType().String() + "'")
+		}
+		if metadata.getPropertyStructFields()[backendName].Type.Kind() == reflect.Map {
+			return errors.New("Map property field '" + fieldName + "' can't be updated on its own")
+		}
+		updatedProperties[backendName] = graphs[0].getProperties()[backendName]
+	}
+	if len(updatedProperties) == 0 {
+		return errors.New("No properties to update")
+	}
+
+	var ID interface{} = graphs[0].getID()
+	customIDPropertyName, customIDValue := metadata.getCustomID(*graphs[0].getValue())
+	if customIDPropertyName != emptyString {
+		ID = customIDValue.Interface()
+	} else if graphs[0].getID() < 0 {
+		return errors.New("Only entities saved in the database can be updated")
+	}
+
+	//The version of the object is the version expected in the database
+	return u.update(ctx, graphs[0], metadata, ID, updatedProperties, newVersionCheck(graphs[0], u.registry, nil))
+}
+
+//updatePropertiesByID writes properties to the entity of the type of object with ID, its internal or custom ID
+func (u *updater) updatePropertiesByID(ctx context.Context, object interface{}, ID interface{}, properties map[string]interface{}) error {
+	var (
+		graphs   []graph
+		metadata metadata
+		err      error
+	)
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if graphs, err = u.graphFactory.get(reflect.ValueOf(object), map[int]bool{labels: true}); err != nil {
+		return err
+	}
+	if metadata, err = u.registry.get(graphs[0].getValue().Type()); err != nil {
+		return err
+	}
+	if err = validateUpdatedProperties(metadata, properties); err != nil {
+		return err
+	}
+
+	//The version of the entity cached in the session, if any, is the version expected in the database
+	var stored graph
+	if customIDPropertyName, _ := metadata.getCustomID(reflect.New(metadata.getType().Elem())); customIDPropertyName != emptyString {
+		stored = u.store.getByCustomID(reflect.New(metadata.getType().Elem()), reflect.TypeOf(graphs[0]), ID)
+	} else if internalID, isInternalID := ID.(int64); isInternalID {
+		if typeOfPrivateNode == reflect.TypeOf(graphs[0]) {
+			stored = u.store.node(internalID)
+		} else {
+			stored = u.store.relationship(internalID)
+		}
+	}
+	var versionCheck *versionCheck
+	if stored != nil {
+		versionCheck = newVersionCheck(stored, u.registry, stored)
+	}
+
+	return u.update(ctx, nil, metadata, ID, properties, versionCheck)
+}
+
+//update sets properties on the entity of metadata with ID. The entity is matched by its custom ID for types with
+//a custom ID, by the labels of g when g isn't nil, and by the version of versionCheck when it isn't nil.
+//The cached graph of the entity and g are refreshed
+func (u *updater) update(ctx context.Context, g graph, metadata metadata, ID interface{}, properties map[string]interface{}, versionCheck *versionCheck) error {
+	var (
+		label                   = metadata.getStructLabel()
+		customIDPropertyName, _ = metadata.getCustomID(reflect.New(metadata.getType().Elem()))
+		predicate               = `ID(n) = $id`
+		parameters              = map[string]interface{}{"id": ID, "properties": properties}
+		records                 []neo4j.Record
+		err                     error
+	)
+	if g != nil {
+		label = g.getLabel()
+	}
+	if customIDPropertyName != emptyString {
+		predicate = `n.` + customIDPropertyName + ` = $id`
+	}
+	if versionCheck != nil {
+		predicate += ` AND coalesce(n.` + versionCheck.propertyName + `, 0) = $version`
+		parameters["version"] = versionCheck.version
+	}
+	match := `MATCH (n:` + label + `)`
+	if _, isNodeMetadata := metadata.(*nodeMetadata); !isNodeMetadata {
+		match = `MATCH ()-[n:` + label + `]->()`
+	}
+	set := `SET n += $properties
+	`
+	if versionPropertyName, _ := metadata.getVersion(reflect.New(metadata.getType().Elem())); versionPropertyName != emptyString {
+		set += `SET n.` + versionPropertyName + ` = coalesce(n.` + versionPropertyName + `, 0) + 1
+	`
+	}
+	cypher := match + `
+	WHERE ` + predicate + `
+	` + set + `RETURN n`
+
+	if records, err = neo4j.Collect(u.cypherExecuter.execContext(ctx, cypher, parameters)); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		if versionCheck != nil {
+			return ErrOptimisticLock
+		}
+		return errors.New("Entity to update wasn't found in the database")
+	}
+
+	var (
+		stored             graph
+		databaseProperties map[string]interface{}
+	)
+	switch entity := records[0].GetByIndex(0).(type) {
+	case neo4j.Node:
+		stored = u.store.node(entity.Id())
+		databaseProperties = entity.Props()
+	case neo4j.Relationship:
+		stored = u.store.relationship(entity.Id())
+		databaseProperties = entity.Props()
+	}
+	if g != nil {
+		u.updateStored(g, metadata, databaseProperties, properties)
+	}
+	if stored != nil {
+		u.updateStored(stored, metadata, databaseProperties, properties)
+		notifyPostSave(u.eventer, u.cypherExecuter.transaction, stored, UPDATE)
+	}
+	return nil
+}
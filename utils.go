@@ -24,6 +24,7 @@ package gogm
 
 import (
 	"reflect"
+	"sort"
 )
 
 func getInternalType(t reflect.Type) reflect.Type {
@@ -58,6 +59,19 @@ func getInternalGraphType(container reflect.Type) reflect.Type {
 	return nil
 }
 
+//getEmbeddingStructFields returns the anonymous fields from container down to its embedded field of type embeddedType
+func getEmbeddingStructFields(container reflect.Type, embeddedType reflect.Type) []reflect.StructField {
+	var embeddingStructFields []reflect.StructField
+	embeddedStructField, isEmbedded := container.FieldByName(embeddedType.Name())
+	if !isEmbedded || !embeddedStructField.Anonymous || embeddedStructField.Type != embeddedType {
+		return embeddingStructFields
+	}
+	for depth := range embeddedStructField.Index {
+		embeddingStructFields = append(embeddingStructFields, container.FieldByIndex(embeddedStructField.Index[:depth+1]))
+	}
+	return embeddingStructFields
+}
+
 func flattenParamters(parameters []map[string]interface{}) map[string]interface{} {
 	var flattenedParamters = map[string]interface{}{}
 	for _, parameter := range parameters {
@@ -82,6 +96,31 @@ func getCyhperFromClauses(cypherClauses map[clause][]string) string {
 	return cypher
 }
 
+//getSortedGraphs returns the graphs of graphs in the order of their keys. Statements built from graphs in this
+//order are the same on every run
+func getSortedGraphs(graphs map[int64]graph) []graph {
+	var keys []int64
+	for key := range graphs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	sortedGraphs := make([]graph, len(keys))
+	for index, key := range keys {
+		sortedGraphs[index] = graphs[key]
+	}
+	return sortedGraphs
+}
+
+//getSortedSignatures returns the sorted signatures of graphs keyed by signature
+func getSortedSignatures(graphs map[string]graph) []string {
+	var signatures []string
+	for signature := range graphs {
+		signatures = append(signatures, signature)
+	}
+	sort.Strings(signatures)
+	return signatures
+}
+
 func indexOfString(slice []string, target string) int {
 	var index = -1
 	for i, s := range slice {
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2020 codingfinest
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+)
+
+//ErrOptimisticLock is returned when saving an entity tagged with a version whose version in the database
+//isn't the version known to the session. The entity was updated or deleted by another session
+var ErrOptimisticLock = errors.New("Optimistic lock failure. Entity was updated or deleted by another session")
+
+//versionCheck is the version an entity is expected to have in the database when it's saved
+type versionCheck struct {
+	propertyName string
+	version      int64
+	_type        reflect.Type
+}
+
+func getVersionBackendName(structFields map[string]*reflect.StructField) (string, error) {
+	var versionBackendName string
+	for backendName, structField := range structFields {
+		if len(getNamespacedTag(structField.Tag).get(versionTag)) > 0 {
+			if versionBackendName != emptyString {
+				return emptyString, errors.New("Only one field can be tagged 'version'")
+			}
+			switch structField.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				versionBackendName = backendName
+			default:
+				return emptyString, errors.New("Invalid version type. Version type must be an integer")
+			}
+		}
+	}
+	return versionBackendName, nil
+}
+
+//newVersionCheck returns the version check of g on save, or nil when g isn't versioned. The expected version is the
+//version of stored, the graph of g in the session store, or the version of g for graphs not in the store
+func newVersionCheck(g graph, registry *registry, stored graph) *versionCheck {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return nil
+	}
+	metadata, err := registry.get(g.getValue().Type())
+	if err != nil {
+		return nil
+	}
+	propertyName, versionValue := metadata.getVersion(*g.getValue())
+	if propertyName == emptyString {
+		return nil
+	}
+
+	version := versionAsInt64(versionValue)
+	if stored != nil {
+		version = versionAsInt64(reflect.ValueOf(stored.getProperties()[propertyName]))
+	}
+	return &versionCheck{propertyName, version, versionValue.Type()}
+}
+
+//next returns the version following the expected version, typed as the version field
+func (vc *versionCheck) next() interface{} {
+	return reflect.ValueOf(vc.version + 1).Convert(vc._type).Interface()
+}
+
+func versionAsInt64(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return 0
+}
+
+//unloadGraphVersion sets the version field of the domain object of g to the version in the properties of g
+func unloadGraphVersion(g graph, registry *registry) error {
+	if !g.getValue().IsValid() {
+		return nil
+	}
+	metadata, err := registry.get(g.getValue().Type())
+	if err != nil {
+		return err
+	}
+	propertyName, versionValue := metadata.getVersion(*g.getValue())
+	if propertyName == emptyString || g.getProperties()[propertyName] == nil {
+		return nil
+	}
+	versionValue.Set(reflect.ValueOf(g.getProperties()[propertyName]).Convert(versionValue.Type()))
+	return nil
+}
+
+//hasVersionedGraph tells whether the version of a graph of graphs is checked on save. Versions are checked when
+//graphs are matched by ID, and when new graphs are merged on an existing entity
+func hasVersionedGraph(graphs map[string]graph, mergedGraphs map[string]graph, registry *registry) bool {
+	for signature, g := range graphs {
+		if (g.getID() >= 0 || mergedGraphs[signature] != nil) && newVersionCheck(g, registry, nil) != nil {
+			return true
+		}
+	}
+	return false
+}